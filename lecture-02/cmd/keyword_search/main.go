@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-02/index"
+	"os"
+)
+
+func main() {
+	rewrite := flag.Bool("rewrite", false, "search for the corrections of words without postings")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Println("Usage: cmd [-rewrite] <file> <query>")
+		os.Exit(-1)
+	}
+
+	filename, query := flag.Arg(0), flag.Arg(1)
+	options := index.RefinementOptions{ExcludingStopWords: true}
+
+	ii := index.NewInvertedIndex()
+	err := ii.ReadFromFile(filename, 0.75, 1.25, options)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	sc := index.NewSpellingCorrector(ii, 3)
+	suggestions, rewritten := sc.CorrectQuery(ii, query, options)
+	if len(suggestions) > 0 {
+		if *rewrite {
+			fmt.Printf("Showing results for: %s\n", rewritten)
+			query = rewritten
+		} else {
+			fmt.Printf("Did you mean: %s\n", rewritten)
+		}
+	}
+
+	docPostings := ii.ProcessQuery(query, options)
+
+	// number of docs to return
+	k := 3
+	for i, posting := range docPostings {
+		if i >= k {
+			break
+		}
+		fmt.Printf("%d %.3f %s\n", i+1, posting.BM25, ii.GetDocByID(posting.DocID).Raw)
+	}
+	return
+}
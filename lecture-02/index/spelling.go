@@ -0,0 +1,84 @@
+package index
+
+import (
+	"sort"
+	"strings"
+
+	qgram "github.com/ZhengHe-MD/ir-freiburg.git/lecture-05/index"
+)
+
+// Suggestion is the correction of a query word without postings.
+type Suggestion struct {
+	Word       string
+	Correction string
+	// ED is the Damerau edit distance of Word and Correction.
+	ED int
+}
+
+// SpellingCorrector suggests corrections for query words that are not in the
+// vocabulary, from a q-gram index over the vocabulary whose scores are the
+// document frequencies, so the more common of two equally close words wins.
+type SpellingCorrector struct {
+	vocabulary *qgram.QGramIndex
+}
+
+func NewSpellingCorrector(ii *InvertedIndex, q int) *SpellingCorrector {
+	words := make([]string, 0, len(ii.invertedLists))
+	for word := range ii.invertedLists {
+		words = append(words, word)
+	}
+	// deterministic word ids
+	sort.Strings(words)
+
+	vocabulary := qgram.NewQGramIndex(q)
+	for _, word := range words {
+		vocabulary.AddEntity(qgram.Entity{Name: word, Score: len(ii.invertedLists[word])})
+	}
+	return &SpellingCorrector{vocabulary: vocabulary}
+}
+
+// Suggest returns the closest word of the vocabulary, allowing one error per
+// four characters but at least one.
+func (sc *SpellingCorrector) Suggest(word string) (suggestion Suggestion, ok bool) {
+	word = strings.ToLower(word)
+	delta := len(word) / 4
+	if delta < 1 {
+		delta = 1
+	}
+
+	matches, _ := sc.vocabulary.FindMatchesWithMode(word, delta, qgram.DamerauMatch)
+	if len(matches) == 0 {
+		return
+	}
+	best := qgram.RankMatches(matches)[0]
+	return Suggestion{Word: word, Correction: best.Entity.Name, ED: best.PED}, true
+}
+
+// CorrectQuery suggests a correction for every word of the query that
+// ProcessQuery finds no postings for, and returns the query with these words
+// replaced by their corrections.
+func (sc *SpellingCorrector) CorrectQuery(ii *InvertedIndex, query string, options RefinementOptions) (suggestions []Suggestion, rewritten string) {
+	var words []string
+	for _, word := range nonAlphaCharRegex.Split(query, -1) {
+		if len(word) == 0 {
+			continue
+		}
+
+		if options.ExcludingStopWords && IsStopWord(word) {
+			words = append(words, word)
+			continue
+		}
+
+		// the vocabulary is lowercased, see ReadFromFile
+		lower := strings.ToLower(word)
+		if len(ii.invertedLists[lower]) == 0 {
+			if suggestion, ok := sc.Suggest(lower); ok && suggestion.Correction != lower {
+				suggestions = append(suggestions, suggestion)
+				word = suggestion.Correction
+			}
+		}
+		words = append(words, word)
+	}
+	rewritten = strings.Join(words, " ")
+	return
+}
@@ -0,0 +1,57 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpellingCorrector_Suggest(t *testing.T) {
+	tests := []struct {
+		givenWord      string
+		wantSuggestion Suggestion
+		wantOK         bool
+	}{
+		{"animatd", Suggestion{"animatd", "animated", 1}, true},
+		{"aniamted", Suggestion{"aniamted", "animated", 1}, true},
+		{"Shrot", Suggestion{"shrot", "short", 1}, true},
+		{"Movie", Suggestion{"movie", "movie", 0}, true},
+		{"lebowski", Suggestion{}, false},
+	}
+
+	ii := NewInvertedIndex()
+	assert.NoError(t, ii.ReadFromFile("example.txt", 0.75, 1.75, RefinementOptions{}))
+	sc := NewSpellingCorrector(ii, 3)
+	for _, tt := range tests {
+		suggestion, ok := sc.Suggest(tt.givenWord)
+		assert.Equal(t, tt.wantOK, ok, tt.givenWord)
+		assert.Equal(t, tt.wantSuggestion, suggestion, tt.givenWord)
+	}
+}
+
+func TestSpellingCorrector_CorrectQuery(t *testing.T) {
+	tests := []struct {
+		givenQuery      string
+		givenOptions    RefinementOptions
+		wantSuggestions []Suggestion
+		wantRewritten   string
+	}{
+		{"animated film", RefinementOptions{}, nil, "animated film"},
+		{"animatd shrot", RefinementOptions{}, []Suggestion{{"animatd", "animated", 1}, {"shrot", "short", 1}}, "animated short"},
+		{"animatd lebowski", RefinementOptions{}, []Suggestion{{"animatd", "animated", 1}}, "animated lebowski"},
+		// words are looked up lowercased, like the vocabulary
+		{"Movie", RefinementOptions{}, nil, "Movie"},
+		{"Animated Shrot", RefinementOptions{}, []Suggestion{{"shrot", "short", 1}}, "Animated short"},
+		// stop words are not searched for, so they are not corrected either
+		{"the animatd", RefinementOptions{ExcludingStopWords: true}, []Suggestion{{"animatd", "animated", 1}}, "the animated"},
+	}
+
+	ii := NewInvertedIndex()
+	assert.NoError(t, ii.ReadFromFile("example.txt", 0.75, 1.75, RefinementOptions{}))
+	sc := NewSpellingCorrector(ii, 3)
+	for _, tt := range tests {
+		suggestions, rewritten := sc.CorrectQuery(ii, tt.givenQuery, tt.givenOptions)
+		assert.Equal(t, tt.wantSuggestions, suggestions, tt.givenQuery)
+		assert.Equal(t, tt.wantRewritten, rewritten, tt.givenQuery)
+	}
+}
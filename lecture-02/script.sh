@@ -35,3 +35,8 @@ go run cmd/benchmark/main.go ../data/movies.txt ../data/movies-benchmark-minus-1
 # MP@3: 0.556
 # MP@R: 0.466
 # MAP: 0.471
+
+# keyword search with "did you mean" suggestions for words without postings,
+# -rewrite searches for the suggestion instead
+go run cmd/keyword_search/main.go ../data/movies.txt "the big lebauski"
+go run cmd/keyword_search/main.go -rewrite ../data/movies.txt "the big lebauski"
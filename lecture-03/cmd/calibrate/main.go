@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/intersection"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Println("Usage: cmd <cost model file>")
+		os.Exit(-1)
+	}
+
+	c := Calibrate(DefaultCalibrationConfig)
+	if err := c.Save(os.Args[1]); err != nil {
+		fmt.Printf("Save err %v\n", err)
+		os.Exit(-1)
+	}
+	fmt.Printf("%+v\n", c)
+}
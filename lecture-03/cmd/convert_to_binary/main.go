@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Println("Usage: cmd <text file> <binary file>")
+		os.Exit(-1)
+	}
+
+	if err := ConvertTextToBinary(os.Args[1], os.Args[2]); err != nil {
+		fmt.Printf("ConvertTextToBinary err %v\n", err)
+		os.Exit(-1)
+	}
+}
@@ -45,21 +45,17 @@ func prepareDataWithSentinel() (postingLists []*PostingList, err error) {
 }
 
 func prepareDataWithSkipPointer() (postingLists []*PostingList, err error) {
-	l1, l2, l3 := NewPostingList(), NewPostingList(), NewPostingList()
+	return prepareDataWithSkips(FixedSkips(2000))
+}
 
-	if err = l1.ReadFromFileWithSkipPointer("../data/bowling.txt", 2000); err != nil {
+func prepareDataWithSkips(placement SkipPlacement) (postingLists []*PostingList, err error) {
+	if postingLists, err = prepareData(); err != nil {
 		return
 	}
 
-	if err = l2.ReadFromFileWithSkipPointer("../data/film.txt", 2000); err != nil {
-		return
+	for _, l := range postingLists {
+		l.BuildSkips(placement)
 	}
-
-	if err = l3.ReadFromFileWithSkipPointer("../data/rug.txt", 2000); err != nil {
-		return
-	}
-
-	postingLists = append(postingLists, l1, l2, l3)
 	return
 }
 
@@ -141,6 +137,28 @@ func BenchmarkIntersectWithSkipPointer(b *testing.B) {
 	}
 }
 
+func benchmarkIntersectWithSkips(b *testing.B, placement SkipPlacement) {
+	postingLists, err := prepareDataWithSkips(placement)
+	assert.NoError(b, err)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(postingLists); j++ {
+			for k := 0; k < j; k++ {
+				IntersectWithSkipPointer(postingLists[j], postingLists[k])
+			}
+		}
+	}
+}
+
+func BenchmarkIntersectWithSqrtSkips(b *testing.B) {
+	benchmarkIntersectWithSkips(b, SqrtSkips())
+}
+
+func BenchmarkIntersectWithMultiLevelSkips(b *testing.B) {
+	benchmarkIntersectWithSkips(b, MultiLevelSkips(16, 3))
+}
+
 func BenchmarkIntersectHybrid(b *testing.B) {
 	postingLists, err := prepareData()
 	assert.NoError(b, err)
@@ -153,3 +171,68 @@ func BenchmarkIntersectHybrid(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkIntersectWithCalibratedCostModel(b *testing.B) {
+	postingLists, err := prepareDataWithSkipPointer()
+	assert.NoError(b, err)
+	c := Calibrate(DefaultCalibrationConfig)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(postingLists); j++ {
+			for k := 0; k < j; k++ {
+				IntersectWithCostModel(c, postingLists[j], postingLists[k])
+			}
+		}
+	}
+}
+
+func benchmarkIntersectParallel(b *testing.B, numWorkers int) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(postingLists); j++ {
+			for k := 0; k < j; k++ {
+				IntersectParallel(postingLists[j], postingLists[k], numWorkers, IntersectHybrid)
+			}
+		}
+	}
+}
+
+func BenchmarkIntersectParallel2(b *testing.B) {
+	benchmarkIntersectParallel(b, 2)
+}
+
+func BenchmarkIntersectParallel4(b *testing.B) {
+	benchmarkIntersectParallel(b, 4)
+}
+
+func BenchmarkIntersectParallel8(b *testing.B) {
+	benchmarkIntersectParallel(b, 8)
+}
+
+func BenchmarkIntersectTopK(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	for i := 0; i < b.N; i++ {
+		IntersectTopK(postingLists, 10, SumAggregation)
+	}
+}
+
+func BenchmarkIntersectTopKImpactOrdered(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	var impactOrdered []*ImpactOrderedList
+	for _, l := range postingLists {
+		impactOrdered = append(impactOrdered, NewImpactOrderedList(l))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		IntersectTopKImpactOrdered(impactOrdered, 10, SumAggregation)
+	}
+}
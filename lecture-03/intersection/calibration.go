@@ -0,0 +1,125 @@
+package intersection
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+type CalibrationConfig struct {
+	// LongSizes are the sizes of the longer synthetic list.
+	LongSizes []int
+	// Ratios are the size ratios between the longer and the shorter list.
+	Ratios []int
+	// Repeat is the number of runs per measurement, the fastest one is kept.
+	Repeat int
+	Seed   int64
+}
+
+var DefaultCalibrationConfig = CalibrationConfig{
+	LongSizes: []int{10000, 100000, 1000000},
+	Ratios:    []int{1, 4, 16, 64, 256, 1024},
+	Repeat:    5,
+	Seed:      1,
+}
+
+// Calibrate runs every algorithm on synthetic list pairs and fits, for each
+// algorithm, the constant that minimizes the squared error between the
+// measured time and constant * steps (see CostModel).
+func Calibrate(config CalibrationConfig) (c CostModel) {
+	r := rand.New(rand.NewSource(config.Seed))
+	algorithms := []Algorithm{LinearMerge, GallopingSearch, BinarySearch, SkipPointer}
+	sumTimesSteps := make([]float64, len(algorithms))
+	sumSquaredSteps := make([]float64, len(algorithms))
+
+	for _, n := range config.LongSizes {
+		for _, ratio := range config.Ratios {
+			k := n / ratio
+			if k == 0 {
+				continue
+			}
+			l1, l2 := syntheticPair(r, k, n)
+
+			for i, algorithm := range algorithms {
+				if algorithm == SkipPointer && !l2.HasSkips() {
+					continue
+				}
+				s := steps(algorithm, k, n, l2.SkipDistances())
+				t := measure(config.Repeat, func() {
+					Intersect(algorithm, l1, l2)
+				})
+				sumTimesSteps[i] += t * s
+				sumSquaredSteps[i] += s * s
+			}
+		}
+	}
+
+	for i, algorithm := range algorithms {
+		if sumSquaredSteps[i] > 0 {
+			c.setConstant(algorithm, sumTimesSteps[i]/sumSquaredSteps[i])
+		}
+	}
+	return
+}
+
+func measure(repeat int, f func()) float64 {
+	best := math.Inf(1)
+	for i := 0; i < repeat || i == 0; i++ {
+		start := time.Now()
+		f()
+		if d := float64(time.Since(start).Nanoseconds()); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// syntheticPair returns a list of size k and a list of size n with √n skip
+// distance. About half of the docIDs of the shorter list are in the longer one.
+func syntheticPair(r *rand.Rand, k, n int) (short, long *PostingList) {
+	longIds := randomSortedIds(r, n, 10)
+	long = NewPostingList()
+	long.Reserve(n)
+	for _, id := range longIds {
+		long.AddPosting(id, 1)
+	}
+	long.BuildSkips(SqrtSkips())
+
+	shortIds := randomSortedIds(r, k, 10*n/k)
+	for i := 0; i < k; i += 2 {
+		shortIds[i] = longIds[r.Intn(n)]
+	}
+	sortAndDedup(&shortIds)
+	short = NewPostingList()
+	short.Reserve(len(shortIds))
+	for _, id := range shortIds {
+		short.AddPosting(id, 1)
+	}
+	return
+}
+
+func randomSortedIds(r *rand.Rand, n, maxGap int) []int64 {
+	ids := make([]int64, n)
+	var id int64
+	for i := range ids {
+		id += 1 + int64(r.Intn(maxGap))
+		ids[i] = id
+	}
+	return ids
+}
+
+func sortAndDedup(ids *[]int64) {
+	s := *ids
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	var n int
+	for i := range s {
+		if i == 0 || s[i] != s[n-1] {
+			s[n] = s[i]
+			n++
+		}
+	}
+	*ids = s[:n]
+}
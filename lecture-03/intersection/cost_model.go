@@ -0,0 +1,204 @@
+package intersection
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+type Algorithm int
+
+const (
+	LinearMerge Algorithm = iota
+	GallopingSearch
+	BinarySearch
+	SkipPointer
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case LinearMerge:
+		return "linear merge"
+	case GallopingSearch:
+		return "galloping search"
+	case BinarySearch:
+		return "binary search"
+	case SkipPointer:
+		return "skip pointer"
+	default:
+		return "unknown"
+	}
+}
+
+// CostModel estimates the running time of each algorithm as a constant times
+// the number of basic steps it does for lists of sizes k ≤ n:
+//
+//	linear merge:     k + n
+//	galloping search: k * (1 + log2(1 + n/k))
+//	binary search:    k * (1 + log2(n))
+//	skip pointer:     k + n/s₀ + min(n, k*(sₗ + s₀/s₁ + ... + sₗ₋₁/sₗ)), s₀
+//	                  being the coarsest and sₗ the finest skip distance
+//
+// The constants are nanoseconds per step, see Calibrate.
+type CostModel struct {
+	LinearMerge     float64 `json:"linear_merge"`
+	GallopingSearch float64 `json:"galloping_search"`
+	BinarySearch    float64 `json:"binary_search"`
+	SkipPointer     float64 `json:"skip_pointer"`
+}
+
+// DefaultCostModel was calibrated on the synthetic lists of
+// DefaultCalibrationConfig with go1.27 on a single core Intel Xeon (linux/amd64)
+// by `go run cmd/calibrate/main.go cost_model.json`, rounded, see script.sh.
+// Run it to get constants for your machine.
+var DefaultCostModel = CostModel{
+	LinearMerge:     5.2,
+	GallopingSearch: 9.7,
+	BinarySearch:    1.9,
+	SkipPointer:     4.7,
+}
+
+func steps(algorithm Algorithm, k, n int, skipDistances []int) float64 {
+	fk, fn := float64(k), float64(n)
+	switch algorithm {
+	case LinearMerge:
+		return fk + fn
+	case GallopingSearch:
+		return fk * (1 + math.Log2(1+fn/fk))
+	case BinarySearch:
+		return fk * (1 + math.Log2(fn))
+	case SkipPointer:
+		// per lookup: the whole finest level, and up to one fanout per
+		// coarser level
+		perLookup := float64(skipDistances[len(skipDistances)-1])
+		for l := 1; l < len(skipDistances); l++ {
+			perLookup += float64(skipDistances[l-1] / skipDistances[l])
+		}
+		return fk + fn/float64(skipDistances[0]) + math.Min(fn, fk*perLookup)
+	default:
+		return math.Inf(1)
+	}
+}
+
+func (c CostModel) constant(algorithm Algorithm) float64 {
+	switch algorithm {
+	case LinearMerge:
+		return c.LinearMerge
+	case GallopingSearch:
+		return c.GallopingSearch
+	case BinarySearch:
+		return c.BinarySearch
+	case SkipPointer:
+		return c.SkipPointer
+	default:
+		return math.Inf(1)
+	}
+}
+
+func (c *CostModel) setConstant(algorithm Algorithm, v float64) {
+	switch algorithm {
+	case LinearMerge:
+		c.LinearMerge = v
+	case GallopingSearch:
+		c.GallopingSearch = v
+	case BinarySearch:
+		c.BinarySearch = v
+	case SkipPointer:
+		c.SkipPointer = v
+	}
+}
+
+// Cost returns the estimated running time in nanoseconds of intersecting l1
+// and l2 with the given algorithm. Skip pointers are only considered when the
+// longer list has them, otherwise the cost is +Inf.
+func (c CostModel) Cost(algorithm Algorithm, l1, l2 *PostingList) float64 {
+	if l1.Size() > l2.Size() {
+		l1, l2 = l2, l1
+	}
+	k, n := l1.Size(), l2.Size()
+	if k == 0 {
+		return 0
+	}
+
+	if algorithm == SkipPointer && !l2.HasSkips() {
+		return math.Inf(1)
+	}
+	return c.constant(algorithm) * steps(algorithm, k, n, l2.SkipDistances())
+}
+
+// Choose returns the algorithm with the least estimated cost for l1 and l2.
+func (c CostModel) Choose(l1, l2 *PostingList) Algorithm {
+	best, bestCost := LinearMerge, c.Cost(LinearMerge, l1, l2)
+	for _, algorithm := range []Algorithm{GallopingSearch, BinarySearch, SkipPointer} {
+		if cost := c.Cost(algorithm, l1, l2); cost < bestCost {
+			best, bestCost = algorithm, cost
+		}
+	}
+	return best
+}
+
+// Intersect intersects l1 and l2 with the given algorithm.
+func Intersect(algorithm Algorithm, l1, l2 *PostingList) *PostingList {
+	if l1.Size() > l2.Size() {
+		l1, l2 = l2, l1
+	}
+	switch algorithm {
+	case GallopingSearch:
+		return IntersectWithGallopingSearch(l1, l2)
+	case BinarySearch:
+		return IntersectWithBinarySearchInLongerRemainder(l1, l2)
+	case SkipPointer:
+		return IntersectWithSkipPointer(l1, l2)
+	default:
+		return IntersectWithLessConditionalParts(l1, l2)
+	}
+}
+
+// IntersectWithCostModel intersects l1 and l2 with the algorithm the cost model
+// estimates to be the fastest.
+func IntersectWithCostModel(c CostModel, l1, l2 *PostingList) *PostingList {
+	return Intersect(c.Choose(l1, l2), l1, l2)
+}
+
+// IntersectAllWithCostModel intersects all lists, from the shortest to the
+// longest one, choosing an algorithm for every step. It also returns the
+// chosen plan.
+func IntersectAllWithCostModel(c CostModel, lists []*PostingList) (ret *PostingList, plan []Algorithm) {
+	if len(lists) == 0 {
+		return NewPostingList(), nil
+	}
+
+	sorted := make([]*PostingList, len(lists))
+	copy(sorted, lists)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Size() < sorted[j].Size()
+	})
+
+	ret = sorted[0]
+	for _, l := range sorted[1:] {
+		algorithm := c.Choose(ret, l)
+		plan = append(plan, algorithm)
+		ret = Intersect(algorithm, ret, l)
+	}
+	return
+}
+
+func (c CostModel) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+func LoadCostModel(filename string) (c CostModel, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &c)
+	return
+}
@@ -0,0 +1,71 @@
+package intersection
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostModel_Choose(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	l1, l2 := syntheticPair(r, 10000, 10000)
+	l3, l4 := syntheticPair(r, 10, 100000)
+	// without skips only the list sizes matter
+	l2.BuildSkips(FixedSkips(0))
+
+	assert.Equal(t, LinearMerge, DefaultCostModel.Choose(l1, l2))
+	assert.NotEqual(t, LinearMerge, DefaultCostModel.Choose(l3, l4))
+}
+
+func TestIntersectWithCostModel(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	short, long := syntheticPair(r, 100, 10000)
+	want := IntersectBasic(short, long).String()
+
+	for _, algorithm := range []Algorithm{LinearMerge, GallopingSearch, BinarySearch, SkipPointer} {
+		assert.Equal(t, want, Intersect(algorithm, short, long).String(), algorithm.String())
+		assert.Equal(t, want, Intersect(algorithm, long, short).String(), algorithm.String())
+	}
+	assert.Equal(t, want, IntersectHybrid(long, short).String())
+
+	l1, l2, l3 := NewPostingList(), NewPostingList(), NewPostingList()
+	assert.NoError(t, l1.ReadFromFile("../data/example1.txt"))
+	assert.NoError(t, l2.ReadFromFile("../data/example2.txt"))
+	assert.NoError(t, l3.ReadFromFile("../data/example3.txt"))
+
+	ret, plan := IntersectAllWithCostModel(DefaultCostModel, []*PostingList{l1, l2})
+	assert.Equal(t, "[(2, 9), (6, 5)]", ret.String())
+	assert.Len(t, plan, 1)
+
+	ret, plan = IntersectAllWithCostModel(DefaultCostModel, []*PostingList{l1, l2, l3})
+	assert.Equal(t, "[]", ret.String())
+	assert.Len(t, plan, 2)
+}
+
+func TestCalibrate(t *testing.T) {
+	c := Calibrate(CalibrationConfig{
+		LongSizes: []int{1000, 10000},
+		Ratios:    []int{1, 10, 100},
+		Repeat:    1,
+		Seed:      1,
+	})
+	assert.True(t, c.LinearMerge > 0)
+	assert.True(t, c.GallopingSearch > 0)
+	assert.True(t, c.BinarySearch > 0)
+	assert.True(t, c.SkipPointer > 0)
+
+	dir, err := ioutil.TempDir("", "cost_model")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "cost_model.json")
+	assert.NoError(t, c.Save(filename))
+	loaded, err := LoadCostModel(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, c, loaded)
+}
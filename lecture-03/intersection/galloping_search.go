@@ -36,10 +36,14 @@ func gallopingSearch(nextId int64, startPos int, postingList *PostingList) (foun
 
 	gap := 1
 	pos := startPos + gap
-	for postingList.GetId(pos) < nextId {
+	for pos < postingList.Size() && postingList.GetId(pos) < nextId {
 		gap = gap + gap
 		pos = pos + gap
-		if pos >= postingList.Size() {
+	}
+	if pos >= postingList.Size() {
+		// the last jump went past the end, nextId can still be in the tail
+		pos = postingList.Size() - 1
+		if postingList.GetId(pos) < nextId {
 			return false, -1
 		}
 	}
@@ -18,3 +18,16 @@ func TestIntersectWithGallopingSearch(t *testing.T) {
 	assert.Equal(t, "[(2, 9), (6, 5)]", ret1.String())
 	assert.Equal(t, "[]", ret2.String())
 }
+
+func TestIntersectWithGallopingSearch_Tail(t *testing.T) {
+	l1, l2 := NewPostingList(), NewPostingList()
+	l1.Reserve(2)
+	l1.AddPosting(9, 1)
+	l1.AddPosting(10, 1)
+	l2.Reserve(10)
+	for i := 1; i <= 10; i++ {
+		l2.AddPosting(int64(i), 1)
+	}
+
+	assert.Equal(t, "[(9, 2), (10, 2)]", IntersectWithGallopingSearch(l1, l2).String())
+}
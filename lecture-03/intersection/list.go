@@ -0,0 +1,41 @@
+package intersection
+
+import . "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+
+// List is the access pattern the skipping intersection needs, it is
+// implemented by *PostingList and by representations that only decode what is
+// accessed, such as compressed lists.
+type List interface {
+	Size() int
+	GetId(idx int) int64
+	GetScore(idx int) int64
+	// SkipTo returns the first position at or after from whose docID is not
+	// smaller than id, or Size() if there is none.
+	SkipTo(id int64, from int) int
+}
+
+// IntersectWithSkipTo leapfrogs between l1 and l2 with SkipTo, it works for
+// any List.
+func IntersectWithSkipTo(l1, l2 List) (ret *PostingList) {
+	ret = NewPostingList()
+	minSize := l1.Size()
+	if l2.Size() < minSize {
+		minSize = l2.Size()
+	}
+	ret.Reserve(minSize)
+
+	var i1, i2 int
+	for i1 < l1.Size() && i2 < l2.Size() {
+		id1, id2 := l1.GetId(i1), l2.GetId(i2)
+		if id1 < id2 {
+			i1 = l1.SkipTo(id2, i1)
+		} else if id2 < id1 {
+			i2 = l2.SkipTo(id1, i2)
+		} else {
+			ret.AddPosting(id1, l1.GetScore(i1)+l2.GetScore(i2))
+			i1++
+			i2++
+		}
+	}
+	return
+}
@@ -0,0 +1,76 @@
+package intersection
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+type IntersectFunc func(l1, l2 *PostingList) *PostingList
+
+// IntersectParallel splits the docID space into numWorkers ranges holding
+// about the same number of postings of the longer list, intersects the parts of
+// both lists in each range concurrently with intersect, and concatenates the
+// results. numWorkers ≤ 0 means runtime.NumCPU().
+//
+// NOTE: the parts are views without skips or sentinels, so intersect must
+// not rely on them.
+func IntersectParallel(l1, l2 *PostingList, numWorkers int, intersect IntersectFunc) (ret *PostingList) {
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	if l1.Size() > l2.Size() {
+		l1, l2 = l2, l1
+	}
+	if numWorkers > l2.Size() {
+		numWorkers = l2.Size()
+	}
+	if numWorkers <= 1 {
+		return intersect(l1, l2)
+	}
+
+	// bounds1[p] and bounds2[p] are the first positions of partition p, the
+	// split docIDs are taken at even positions of the longer list
+	bounds1, bounds2 := make([]int, numWorkers+1), make([]int, numWorkers+1)
+	bounds1[numWorkers], bounds2[numWorkers] = l1.Size(), l2.Size()
+	for p := 1; p < numWorkers; p++ {
+		bounds2[p] = p * l2.Size() / numWorkers
+		bounds1[p] = lowerBound(l1, l2.GetId(bounds2[p]))
+	}
+
+	results := make([]*PostingList, numWorkers)
+	var wg sync.WaitGroup
+	for p := 0; p < numWorkers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			results[p] = intersect(
+				l1.Slice(bounds1[p], bounds1[p+1]),
+				l2.Slice(bounds2[p], bounds2[p+1]),
+			)
+		}(p)
+	}
+	wg.Wait()
+
+	var size int
+	for _, r := range results {
+		size += r.Size()
+	}
+	ret = NewPostingList()
+	ret.Reserve(size)
+	for _, r := range results {
+		r.Iterate(func(i int) {
+			ret.AddPosting(r.GetId(i), r.GetScore(i))
+		})
+	}
+	return
+}
+
+// lowerBound returns the first position whose docID is not smaller than id.
+func lowerBound(l *PostingList, id int64) int {
+	return sort.Search(l.Size(), func(i int) bool {
+		return l.GetId(i) >= id
+	})
+}
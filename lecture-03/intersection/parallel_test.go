@@ -0,0 +1,27 @@
+package intersection
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntersectParallel(t *testing.T) {
+	l1, l2, l3 := NewPostingList(), NewPostingList(), NewPostingList()
+	assert.NoError(t, l1.ReadFromFile("../data/example1.txt"))
+	assert.NoError(t, l2.ReadFromFile("../data/example2.txt"))
+	assert.NoError(t, l3.ReadFromFile("../data/example3.txt"))
+
+	assert.Equal(t, "[(2, 9), (6, 5)]", IntersectParallel(l1, l2, 2, IntersectBasic).String())
+	assert.Equal(t, "[]", IntersectParallel(l1, l3, 2, IntersectBasic).String())
+
+	short, long := syntheticPair(rand.New(rand.NewSource(1)), 1000, 100000)
+	want := IntersectBasic(short, long).String()
+	for _, numWorkers := range []int{0, 1, 3, 8} {
+		for _, intersect := range []IntersectFunc{IntersectBasic, IntersectWithGallopingSearch, IntersectHybrid} {
+			assert.Equal(t, want, IntersectParallel(short, long, numWorkers, intersect).String())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package intersection
+
+import "container/heap"
+
+type Posting struct {
+	Id    int64
+	Score int64
+}
+
+// less orders postings by score, and by descending docID on ties so smaller
+// docIDs win.
+func (p Posting) less(o Posting) bool {
+	if p.Score != o.Score {
+		return p.Score < o.Score
+	}
+	return p.Id > o.Id
+}
+
+type PostingHeap []Posting
+
+func (m PostingHeap) Len() int           { return len(m) }
+func (m PostingHeap) Less(i, j int) bool { return m[i].less(m[j]) }
+func (m PostingHeap) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+func (m *PostingHeap) Push(x interface{}) {
+	*m = append(*m, x.(Posting))
+}
+
+func (m *PostingHeap) Pop() interface{} {
+	old := *m
+	n := len(old)
+	x := old[n-1]
+	*m = old[0 : n-1]
+	return x
+}
+
+// CappedPostingHeap keeps the cap best postings seen so far.
+type CappedPostingHeap struct {
+	ph  PostingHeap
+	cap int
+}
+
+func NewCappedPostingHeap(cap int) *CappedPostingHeap {
+	return &CappedPostingHeap{cap: cap}
+}
+
+func (m *CappedPostingHeap) Push(x Posting) {
+	if len(m.ph) < m.cap {
+		heap.Push(&m.ph, x)
+		return
+	}
+	if m.cap > 0 && m.ph[0].less(x) {
+		m.ph[0] = x
+		heap.Fix(&m.ph, 0)
+	}
+}
+
+func (m *CappedPostingHeap) Full() bool {
+	return len(m.ph) >= m.cap
+}
+
+// Min returns the worst posting kept, only valid if the heap is not empty.
+func (m *CappedPostingHeap) Min() Posting {
+	return m.ph[0]
+}
+
+// Sorted empties the heap and returns its postings from the best to the worst.
+func (m *CappedPostingHeap) Sorted() []Posting {
+	ret := make([]Posting, len(m.ph))
+	for i := len(ret) - 1; i >= 0; i-- {
+		ret[i] = heap.Pop(&m.ph).(Posting)
+	}
+	return ret
+}
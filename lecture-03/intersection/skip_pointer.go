@@ -4,6 +4,11 @@ import (
 	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
 )
 
+// IntersectWithSkipPointer follows the skips of both lists, see
+// PostingList.BuildSkips. Lists without skips are scanned linearly.
+//
+// NOTE: this is the loop of IntersectWithSkipTo on the concrete type, so the
+// skip pointer benchmarks and the cost model don't measure interface calls.
 func IntersectWithSkipPointer(l1, l2 *PostingList) (ret *PostingList) {
 	ret = NewPostingList()
 	minSize := l1.Size()
@@ -14,27 +19,13 @@ func IntersectWithSkipPointer(l1, l2 *PostingList) (ret *PostingList) {
 
 	var i1, i2 int
 	for i1 < l1.Size() && i2 < l2.Size() {
-		for i1 < l1.Size() && l1.GetId(i1) < l2.GetId(i2) {
-			i1++
-		}
-		if i1 == l1.Size() {
-			break
-		}
-
-		for i2 < l2.Size() && l2.GetId(i2) < l1.GetId(i1) {
-			nextPos, found := l2.GetSkipPointer(i2)
-			if found && l2.GetId(nextPos) < l1.GetId(i1) {
-				i2 = nextPos + 1
-			} else {
-				i2++
-			}
-		}
-		if i2 == l2.Size() {
-			break
-		}
-
-		if l1.GetId(i1) == l2.GetId(i2) {
-			ret.AddPosting(l1.GetId(i1), l1.GetScore(i1)+l2.GetScore(i2))
+		id1, id2 := l1.GetId(i1), l2.GetId(i2)
+		if id1 < id2 {
+			i1 = l1.SkipTo(id2, i1)
+		} else if id2 < id1 {
+			i2 = l2.SkipTo(id1, i2)
+		} else {
+			ret.AddPosting(id1, l1.GetScore(i1)+l2.GetScore(i2))
 			i1++
 			i2++
 		}
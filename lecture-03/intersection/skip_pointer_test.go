@@ -1,9 +1,11 @@
 package intersection
 
 import (
+	"math/rand"
+	"testing"
+
 	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func TestIntersectWithSkipPointer(t *testing.T) {
@@ -18,3 +20,15 @@ func TestIntersectWithSkipPointer(t *testing.T) {
 	assert.Equal(t, "[(2, 9), (6, 5)]", ret1.String())
 	assert.Equal(t, "[]", ret2.String())
 }
+
+func TestIntersectWithSkipPointer_Placements(t *testing.T) {
+	short, long := syntheticPair(rand.New(rand.NewSource(1)), 1000, 100000)
+	want := IntersectBasic(short, long).String()
+
+	for _, placement := range []SkipPlacement{SqrtSkips(), FixedSkips(16), MultiLevelSkips(16, 3)} {
+		short.BuildSkips(placement)
+		long.BuildSkips(placement)
+		assert.Equal(t, want, IntersectWithSkipPointer(short, long).String())
+		assert.Equal(t, want, IntersectWithSkipPointer(long, short).String())
+	}
+}
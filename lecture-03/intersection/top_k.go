@@ -0,0 +1,162 @@
+package intersection
+
+import (
+	"sort"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+// Aggregation combines the scores of a document in each list. It must be
+// monotone (not decrease when any score increases) for the early exit of
+// IntersectTopKImpactOrdered to be correct.
+type Aggregation func(scores []int64) int64
+
+func SumAggregation(scores []int64) (ret int64) {
+	for _, s := range scores {
+		ret += s
+	}
+	return
+}
+
+func MaxAggregation(scores []int64) (ret int64) {
+	for i, s := range scores {
+		if i == 0 || s > ret {
+			ret = s
+		}
+	}
+	return
+}
+
+// WeightedSumAggregation weights the score of the i-th list with weights[i],
+// the weights must not be negative.
+func WeightedSumAggregation(weights []int64) Aggregation {
+	return func(scores []int64) (ret int64) {
+		for i, s := range scores {
+			ret += weights[i] * s
+		}
+		return
+	}
+}
+
+func toPostingList(postings []Posting) (ret *PostingList) {
+	ret = NewPostingList()
+	ret.Reserve(len(postings))
+	for _, p := range postings {
+		ret.AddPosting(p.Id, p.Score)
+	}
+	return
+}
+
+// IntersectTopK returns the k documents contained in all lists with the highest
+// aggregated scores, ordered by score descending and docID ascending on ties.
+func IntersectTopK(lists []*PostingList, k int, aggregate Aggregation) *PostingList {
+	h := NewCappedPostingHeap(k)
+	if len(lists) == 0 || k <= 0 {
+		return toPostingList(h.Sorted())
+	}
+
+	shortest := 0
+	for i, l := range lists {
+		if l.Size() < lists[shortest].Size() {
+			shortest = i
+		}
+	}
+
+	positions := make([]int, len(lists))
+	scores := make([]int64, len(lists))
+	lists[shortest].Iterate(func(i int) {
+		id := lists[shortest].GetId(i)
+		for j, l := range lists {
+			if j == shortest {
+				scores[j] = l.GetScore(i)
+				continue
+			}
+			positions[j] = l.SkipTo(id, positions[j])
+			if positions[j] == l.Size() || l.GetId(positions[j]) != id {
+				return
+			}
+			scores[j] = l.GetScore(positions[j])
+		}
+		h.Push(Posting{Id: id, Score: aggregate(scores)})
+	})
+	return toPostingList(h.Sorted())
+}
+
+// ImpactOrderedList gives access to the postings of a docID ordered list by
+// decreasing score.
+type ImpactOrderedList struct {
+	list  *PostingList
+	order []int
+}
+
+func NewImpactOrderedList(l *PostingList) *ImpactOrderedList {
+	order := make([]int, l.Size())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return l.GetScore(order[i]) > l.GetScore(order[j])
+	})
+	return &ImpactOrderedList{list: l, order: order}
+}
+
+// IntersectTopKImpactOrdered computes the same result as IntersectTopK with
+// the threshold algorithm: the lists are read in parallel by decreasing score,
+// every newly seen docID is looked up in the other lists with binary search,
+// and reading stops as soon as the k-th best score exceeds the aggregation
+// of the scores at the current depth, since no unseen document can beat it.
+// It also returns the number of postings read in impact order.
+func IntersectTopKImpactOrdered(lists []*ImpactOrderedList, k int, aggregate Aggregation) (ret *PostingList, numSortedAccesses int) {
+	h := NewCappedPostingHeap(k)
+	if len(lists) == 0 || k <= 0 {
+		return toPostingList(h.Sorted()), 0
+	}
+
+	maxDepth := 0
+	for _, l := range lists {
+		if l.list.Size() > maxDepth {
+			maxDepth = l.list.Size()
+		}
+	}
+
+	seen := make(map[int64]bool)
+	scores := make([]int64, len(lists))
+	bounds := make([]int64, len(lists))
+	for depth := 0; depth < maxDepth; depth++ {
+		for i, l := range lists {
+			if depth >= len(l.order) {
+				// a list is exhausted, every document of the result was seen
+				return toPostingList(h.Sorted()), numSortedAccesses
+			}
+			numSortedAccesses++
+			pos := l.order[depth]
+			bounds[i] = l.list.GetScore(pos)
+
+			id := l.list.GetId(pos)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			found := true
+			for j, o := range lists {
+				p := lowerBound(o.list, id)
+				if p == o.list.Size() || o.list.GetId(p) != id {
+					found = false
+					break
+				}
+				scores[j] = o.list.GetScore(p)
+			}
+			if found {
+				h.Push(Posting{Id: id, Score: aggregate(scores)})
+			}
+		}
+
+		// strictly greater, an unseen document with the same score and a
+		// smaller docID would still win the tie
+		if h.Full() && h.Min().Score > aggregate(bounds) {
+			break
+		}
+	}
+	return toPostingList(h.Sorted()), numSortedAccesses
+}
@@ -0,0 +1,59 @@
+package intersection
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntersectTopK(t *testing.T) {
+	l1, l2 := NewPostingList(), NewPostingList()
+	assert.NoError(t, l1.ReadFromFile("../data/example1.txt"))
+	assert.NoError(t, l2.ReadFromFile("../data/example2.txt"))
+	lists := []*PostingList{l1, l2}
+
+	tests := []struct {
+		givenK           int
+		givenAggregation Aggregation
+		want             string
+	}{
+		{1, SumAggregation, "[(2, 9)]"},
+		{5, SumAggregation, "[(2, 9), (6, 5)]"},
+		{2, MaxAggregation, "[(2, 5), (6, 3)]"},
+		{2, WeightedSumAggregation([]int64{1, 10}), "[(2, 45), (6, 32)]"},
+		{0, SumAggregation, "[]"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IntersectTopK(lists, tt.givenK, tt.givenAggregation).String())
+
+		impactOrdered := []*ImpactOrderedList{NewImpactOrderedList(l1), NewImpactOrderedList(l2)}
+		ret, _ := IntersectTopKImpactOrdered(impactOrdered, tt.givenK, tt.givenAggregation)
+		assert.Equal(t, tt.want, ret.String())
+	}
+}
+
+func TestIntersectTopKImpactOrdered(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var lists []*PostingList
+	var impactOrdered []*ImpactOrderedList
+	for _, n := range []int{20000, 5000, 10000} {
+		l := NewPostingList()
+		l.Reserve(n)
+		for i := 0; i < n; i++ {
+			// dense docIDs so that the lists share many documents
+			l.AddPosting(int64(i*20000/n), r.Int63n(1000))
+		}
+		lists = append(lists, l)
+		impactOrdered = append(impactOrdered, NewImpactOrderedList(l))
+	}
+
+	for _, aggregate := range []Aggregation{SumAggregation, MaxAggregation, WeightedSumAggregation([]int64{3, 1, 2})} {
+		want := IntersectTopK(lists, 10, aggregate)
+		got, numSortedAccesses := IntersectTopKImpactOrdered(impactOrdered, 10, aggregate)
+		assert.Equal(t, want.String(), got.String())
+		assert.True(t, numSortedAccesses < 5000)
+	}
+}
@@ -0,0 +1,46 @@
+package postinglist
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkPostingList_ReadFromFile(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		assert.NoError(b, NewPostingList().ReadFromFile("../data/bowling.txt"))
+	}
+}
+
+func prepareBinaryFile(b *testing.B) (filename string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "postinglist")
+	assert.NoError(b, err)
+	filename = filepath.Join(dir, "bowling.bin")
+	assert.NoError(b, ConvertTextToBinary("../data/bowling.txt", filename))
+	return filename, func() { os.RemoveAll(dir) }
+}
+
+func BenchmarkPostingList_ReadFromBinaryFile(b *testing.B) {
+	filename, cleanup := prepareBinaryFile(b)
+	defer cleanup()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		assert.NoError(b, NewPostingList().ReadFromBinaryFile(filename))
+	}
+}
+
+func BenchmarkPostingList_MmapBinaryFile(b *testing.B) {
+	filename, cleanup := prepareBinaryFile(b)
+	defer cleanup()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		unmap, err := NewPostingList().MmapBinaryFile(filename)
+		assert.NoError(b, err)
+		assert.NoError(b, unmap())
+	}
+}
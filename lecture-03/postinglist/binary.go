@@ -0,0 +1,197 @@
+package postinglist
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// The binary format, all integers are little endian:
+//
+//	magic    [4]byte "IRPL"
+//	version  uint32
+//	flags    uint32, flagSkips if skip levels follow the scores
+//	reserved uint32
+//	count    uint64
+//	docIDs   count x int64
+//	scores   count x int64
+//	skips    numLevels uint64, then per level: distance uint64, n uint64,
+//	         n x int64
+//	checksum uint32, CRC-32 (IEEE) of everything before it
+//
+// The header is 24 bytes so the arrays are 8-byte aligned in a mmaped file.
+const (
+	binaryMagic      = "IRPL"
+	BinaryVersion    = 1
+	binaryHeaderSize = 24
+	flagSkips        = 1 << 0
+)
+
+var (
+	ErrInvalidMagic    = errors.New("not a binary posting list file")
+	ErrInvalidVersion  = errors.New("unsupported binary posting list version")
+	ErrInvalidChecksum = errors.New("binary posting list checksum mismatch")
+	ErrTruncated       = errors.New("binary posting list file is truncated")
+	ErrInvalidSkips    = errors.New("binary posting list has an invalid skip level")
+)
+
+// WriteBinary writes the list, including its skip levels, in the binary format.
+func (m *PostingList) WriteBinary(w io.Writer) (err error) {
+	h := crc32.NewIEEE()
+	bw := bufio.NewWriter(io.MultiWriter(w, h))
+
+	var flags uint32
+	if m.HasSkips() {
+		flags |= flagSkips
+	}
+	header := make([]byte, binaryHeaderSize)
+	copy(header, binaryMagic)
+	binary.LittleEndian.PutUint32(header[4:], BinaryVersion)
+	binary.LittleEndian.PutUint32(header[8:], flags)
+	binary.LittleEndian.PutUint64(header[16:], uint64(m.num))
+	if _, err = bw.Write(header); err != nil {
+		return
+	}
+
+	if err = writeInt64s(bw, m.docIDList[:m.num]); err != nil {
+		return
+	}
+	if err = writeInt64s(bw, m.scoreList[:m.num]); err != nil {
+		return
+	}
+
+	if m.HasSkips() {
+		levels := []int64{int64(len(m.skipLevels))}
+		for _, level := range m.skipLevels {
+			levels = append(levels, int64(level.distance), int64(len(level.ids)))
+			levels = append(levels, level.ids...)
+		}
+		if err = writeInt64s(bw, levels); err != nil {
+			return
+		}
+	}
+
+	if err = bw.Flush(); err != nil {
+		return
+	}
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], h.Sum32())
+	_, err = w.Write(checksum[:])
+	return
+}
+
+func writeInt64s(w io.Writer, values []int64) error {
+	var buf [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], uint64(v))
+		if _, err := w.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *PostingList) WriteToBinaryFile(filename string) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return m.WriteBinary(f)
+}
+
+// ReadFromBinaryFile reads the whole file with a single read and decodes it.
+func (m *PostingList) ReadFromBinaryFile(filename string) (err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	return m.decodeBinary(data)
+}
+
+// ConvertTextToBinary converts a posting list file in the text format (see
+// ReadFromFile) to the binary format.
+func ConvertTextToBinary(textFilename, binaryFilename string) (err error) {
+	m := NewPostingList()
+	if err = m.ReadFromFile(textFilename); err != nil {
+		return
+	}
+	return m.WriteToBinaryFile(binaryFilename)
+}
+
+// decodeBinary decodes data in the binary format. On little endian machines
+// the arrays alias data instead of being copied when data is 8-byte aligned.
+func (m *PostingList) decodeBinary(data []byte) (err error) {
+	if len(data) < binaryHeaderSize+4 {
+		return ErrTruncated
+	}
+	if string(data[:4]) != binaryMagic {
+		return ErrInvalidMagic
+	}
+	if binary.LittleEndian.Uint32(data[4:]) != BinaryVersion {
+		return ErrInvalidVersion
+	}
+	body, checksum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return ErrInvalidChecksum
+	}
+
+	flags := binary.LittleEndian.Uint32(data[8:])
+	n := binary.LittleEndian.Uint64(data[16:])
+	words := body[binaryHeaderSize:]
+	// NOTE: n is untrusted, compare before multiplying to not overflow
+	if n > uint64(len(words))/16 {
+		return ErrTruncated
+	}
+
+	docIDs := int64s(words[:8*n])
+	scores := int64s(words[8*n : 16*n])
+	rest := words[16*n:]
+
+	var levels []skipLevel
+	if flags&flagSkips != 0 {
+		if len(rest) < 8 {
+			return ErrTruncated
+		}
+		numLevels := binary.LittleEndian.Uint64(rest)
+		rest = rest[8:]
+		for l := uint64(0); l < numLevels; l++ {
+			if len(rest) < 16 {
+				return ErrTruncated
+			}
+			distance := binary.LittleEndian.Uint64(rest)
+			size := binary.LittleEndian.Uint64(rest[8:])
+			rest = rest[16:]
+			if size > uint64(len(rest))/8 {
+				return ErrTruncated
+			}
+			// SkipTo divides by the distance
+			if distance < 1 || int(distance) < 1 {
+				return ErrInvalidSkips
+			}
+			levels = append(levels, skipLevel{distance: int(distance), ids: int64s(rest[:8*size])})
+			rest = rest[8*size:]
+		}
+	}
+
+	m.docIDList, m.scoreList, m.skipLevels = docIDs, scores, levels
+	m.cap, m.num = int(n), int(n)
+	return
+}
+
+// copyInt64s decodes b as little endian int64s into a new slice.
+func copyInt64s(b []byte) []int64 {
+	ret := make([]int64, len(b)/8)
+	for i := range ret {
+		ret[i] = int64(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	return ret
+}
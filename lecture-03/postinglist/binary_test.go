@@ -0,0 +1,80 @@
+package postinglist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostingList_Binary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "postinglist")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"example1.txt", "example2.txt", "rug.txt"} {
+		want := NewPostingList()
+		assert.NoError(t, want.ReadFromFile(filepath.Join("../data", name)))
+		want.BuildSkips(MultiLevelSkips(4, 2))
+
+		filename := filepath.Join(dir, name+".bin")
+		assert.NoError(t, want.WriteToBinaryFile(filename))
+
+		got := NewPostingList()
+		assert.NoError(t, got.ReadFromBinaryFile(filename))
+		assert.Equal(t, want.String(), got.String())
+		assert.Equal(t, want.SkipDistances(), got.SkipDistances())
+
+		mapped := NewPostingList()
+		unmap, err := mapped.MmapBinaryFile(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, want.String(), mapped.String())
+		assert.Equal(t, want.SkipTo(1000, 0), mapped.SkipTo(1000, 0))
+		assert.NoError(t, unmap())
+	}
+}
+
+func TestPostingList_BinaryErrors(t *testing.T) {
+	l := NewPostingList()
+	assert.NoError(t, l.ReadFromFile("../data/example1.txt"))
+	var buf bytes.Buffer
+	assert.NoError(t, l.WriteBinary(&buf))
+	data := buf.Bytes()
+
+	corrupted := append([]byte{}, data...)
+	corrupted[binaryHeaderSize] ^= 1
+	assert.Equal(t, ErrInvalidChecksum, NewPostingList().decodeBinary(corrupted))
+
+	wrongVersion := append([]byte{}, data...)
+	wrongVersion[4] = 2
+	assert.Equal(t, ErrInvalidVersion, NewPostingList().decodeBinary(wrongVersion))
+
+	assert.Equal(t, ErrInvalidMagic, NewPostingList().decodeBinary(append([]byte("XXXX"), data[4:]...)))
+	assert.Equal(t, ErrTruncated, NewPostingList().decodeBinary(data[:10]))
+
+	// a huge count with a valid checksum must not overflow the size check
+	huge := append([]byte{}, data...)
+	binary.LittleEndian.PutUint64(huge[16:], 1<<63)
+	assert.Equal(t, ErrTruncated, NewPostingList().decodeBinary(resealed(huge)))
+
+	l.BuildSkips(MultiLevelSkips(2, 1))
+	assert.True(t, l.HasSkips())
+	buf.Reset()
+	assert.NoError(t, l.WriteBinary(&buf))
+	zeroDistance := append([]byte{}, buf.Bytes()...)
+	// the distance of the first level follows numLevels
+	binary.LittleEndian.PutUint64(zeroDistance[binaryHeaderSize+16*l.num+8:], 0)
+	assert.Equal(t, ErrInvalidSkips, NewPostingList().decodeBinary(resealed(zeroDistance)))
+}
+
+// resealed recomputes the checksum of data after modifying it.
+func resealed(data []byte) []byte {
+	body := data[:len(data)-4]
+	binary.LittleEndian.PutUint32(data[len(data)-4:], crc32.ChecksumIEEE(body))
+	return data
+}
@@ -0,0 +1,24 @@
+//go:build amd64 || arm64 || loong64 || mips64 || mips64le || ppc64 || ppc64le || riscv64 || s390x
+// +build amd64 arm64 loong64 mips64 mips64le ppc64 ppc64le riscv64 s390x
+
+package postinglist
+
+import "unsafe"
+
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// int64s reinterprets b as little endian int64s, copying only if the machine
+// is big endian or b is not aligned.
+func int64s(b []byte) []int64 {
+	n := len(b) / 8
+	if n == 0 {
+		return []int64{}
+	}
+	if littleEndian && uintptr(unsafe.Pointer(&b[0]))%8 == 0 {
+		return (*[1 << 40]int64)(unsafe.Pointer(&b[0]))[:n:n]
+	}
+	return copyInt64s(b)
+}
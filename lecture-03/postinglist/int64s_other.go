@@ -0,0 +1,10 @@
+//go:build !amd64 && !arm64 && !loong64 && !mips64 && !mips64le && !ppc64 && !ppc64le && !riscv64 && !s390x
+// +build !amd64,!arm64,!loong64,!mips64,!mips64le,!ppc64,!ppc64le,!riscv64,!s390x
+
+package postinglist
+
+// int64s decodes b as little endian int64s. On 32-bit machines an array type
+// large enough for the zero-copy cast does not exist, so it always copies.
+func int64s(b []byte) []int64 {
+	return copyInt64s(b)
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd
+// +build !linux,!darwin,!freebsd
+
+package postinglist
+
+// MmapBinaryFile falls back to ReadFromBinaryFile where mmap is not available.
+func (m *PostingList) MmapBinaryFile(filename string) (unmap func() error, err error) {
+	if err = m.ReadFromBinaryFile(filename); err != nil {
+		return
+	}
+	unmap = func() error { return nil }
+	return
+}
@@ -0,0 +1,42 @@
+//go:build linux || darwin || freebsd
+// +build linux darwin freebsd
+
+package postinglist
+
+import (
+	"os"
+	"syscall"
+)
+
+// MmapBinaryFile maps a file in the binary format read-only into memory, the
+// docIDs and scores are not copied. The list must not be modified and must not
+// be used after calling the returned unmap function.
+func (m *PostingList) MmapBinaryFile(filename string) (unmap func() error, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() == 0 {
+		err = ErrTruncated
+		return
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return
+	}
+	if err = m.decodeBinary(data); err != nil {
+		_ = syscall.Munmap(data)
+		return
+	}
+	unmap = func() error {
+		return syscall.Munmap(data)
+	}
+	return
+}
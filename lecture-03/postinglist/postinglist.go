@@ -11,11 +11,11 @@ import (
 )
 
 type PostingList struct {
-	cap             int
-	num             int
-	docIDList       []int64
-	scoreList       []int64
-	skipPointerList []int
+	cap        int
+	num        int
+	docIDList  []int64
+	scoreList  []int64
+	skipLevels []skipLevel
 }
 
 func NewPostingList() *PostingList {
@@ -105,51 +105,15 @@ func (m *PostingList) ReadFromFileWithSentinel(filename string) (err error) {
 	return
 }
 
+// ReadFromFileWithSkipPointer reads the list and places numSkipPointers skips
+// evenly, see BuildSkips for other placements.
 func (m *PostingList) ReadFromFileWithSkipPointer(filename string, numSkipPointers int) (err error) {
-	f, err := os.Open(filename)
-	if err != nil {
+	if err = m.ReadFromFile(filename); err != nil {
 		return
 	}
-	defer f.Close()
-
-	scanner := bufio.NewScanner(f)
-	if !scanner.Scan() {
-		err = errors.New("file less than one line")
-		return
-	}
-	firstLine := scanner.Text()
-	n, err := strconv.ParseInt(firstLine, 10, 64)
-	if err != nil {
-		return
+	if numSkipPointers > 0 {
+		m.BuildSkips(FixedSkips(m.num / numSkipPointers))
 	}
-	m.Reserve(int(n))
-
-	gap := int(n) / numSkipPointers
-	for scanner.Scan() {
-		line := scanner.Text()
-		if m.num%gap == 0 && (m.num+gap < int(n)) {
-			m.AddSkipPointer(m.num + gap)
-		} else {
-			m.AddSkipPointer(0)
-		}
-
-		parts := strings.Fields(line)
-		if len(parts) != 2 {
-			err = errors.New("invalid line format")
-			return
-		}
-
-		var id, score int64
-		if id, err = strconv.ParseInt(parts[0], 10, 64); err != nil {
-			return
-		}
-		if score, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
-			return
-		}
-
-		m.AddPosting(id, score)
-	}
-
 	return
 }
 
@@ -159,16 +123,12 @@ func (m *PostingList) AddPosting(id, score int64) {
 	m.num += 1
 }
 
-func (m *PostingList) AddSkipPointer(pos int) {
-	m.skipPointerList[m.num] = pos
-}
-
 func (m *PostingList) Reserve(n int) {
 	m.docIDList = make([]int64, n)
 	m.scoreList = make([]int64, n)
-	m.skipPointerList = make([]int, n)
 	m.cap = n
 	m.num = 0
+	m.skipLevels = nil
 }
 
 func (m *PostingList) Size() int {
@@ -183,8 +143,25 @@ func (m *PostingList) GetScore(idx int) int64 {
 	return m.scoreList[idx]
 }
 
-func (m *PostingList) GetSkipPointer(idx int) (int, bool) {
-	return m.skipPointerList[idx], m.skipPointerList[idx] != 0
+// DocIDs returns the docIDs of the list, the slice shares memory with m.
+func (m *PostingList) DocIDs() []int64 {
+	return m.docIDList[:m.num]
+}
+
+// Scores returns the scores of the list, the slice shares memory with m.
+func (m *PostingList) Scores() []int64 {
+	return m.scoreList[:m.num]
+}
+
+// Slice returns a view of the postings in [from, to), it shares the docIDs and
+// scores with m but has no skips.
+func (m *PostingList) Slice(from, to int) *PostingList {
+	return &PostingList{
+		cap:       to - from,
+		num:       to - from,
+		docIDList: m.docIDList[from:to:to],
+		scoreList: m.scoreList[from:to:to],
+	}
 }
 
 func (m *PostingList) String() string {
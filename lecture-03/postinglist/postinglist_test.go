@@ -13,31 +13,28 @@ func TestPostingList_ReadFromFile(t *testing.T) {
 		{
 			"../data/example1.txt",
 			PostingList{
-				cap:             3,
-				num:             3,
-				docIDList:       []int64{2, 3, 6},
-				scoreList:       []int64{5, 1, 2},
-				skipPointerList: []int{0, 0, 0},
+				cap:       3,
+				num:       3,
+				docIDList: []int64{2, 3, 6},
+				scoreList: []int64{5, 1, 2},
 			},
 		},
 		{
 			"../data/example2.txt",
 			PostingList{
-				cap:             4,
-				num:             4,
-				docIDList:       []int64{1, 2, 4, 6},
-				scoreList:       []int64{1, 4, 3, 3},
-				skipPointerList: []int{0, 0, 0, 0},
+				cap:       4,
+				num:       4,
+				docIDList: []int64{1, 2, 4, 6},
+				scoreList: []int64{1, 4, 3, 3},
 			},
 		},
 		{
 			"../data/example3.txt",
 			PostingList{
-				cap:             2,
-				num:             2,
-				docIDList:       []int64{5, 7},
-				scoreList:       []int64{1, 2},
-				skipPointerList: []int{0, 0},
+				cap:       2,
+				num:       2,
+				docIDList: []int64{5, 7},
+				scoreList: []int64{1, 2},
 			},
 		},
 	}
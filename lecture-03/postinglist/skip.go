@@ -0,0 +1,88 @@
+package postinglist
+
+import "math"
+
+// SkipPlacement returns the skip distances for a list of size n, from the
+// coarsest level to the finest one. Every distance must be a multiple of the
+// next one.
+type SkipPlacement func(n int) []int
+
+// SqrtSkips places one level of skips every √n postings.
+func SqrtSkips() SkipPlacement {
+	return func(n int) []int {
+		return []int{int(math.Sqrt(float64(n)))}
+	}
+}
+
+// FixedSkips places one level of skips every distance postings.
+func FixedSkips(distance int) SkipPlacement {
+	return func(n int) []int {
+		return []int{distance}
+	}
+}
+
+// MultiLevelSkips places skips every fanout, fanout², ..., fanout^levels
+// postings, levels that would not contain any skip are left out.
+func MultiLevelSkips(fanout, levels int) SkipPlacement {
+	return func(n int) (distances []int) {
+		distance := 1
+		for l := 0; l < levels && distance*fanout < n; l++ {
+			distance *= fanout
+			distances = append([]int{distance}, distances...)
+		}
+		return
+	}
+}
+
+// skipLevel stores the docIDs at positions distance, 2*distance, ... so the
+// skip positions themselves need not be stored.
+type skipLevel struct {
+	distance int
+	ids      []int64
+}
+
+// BuildSkips (re)builds the skip levels of the list with the given placement.
+// Distances smaller than 2 are ignored.
+func (m *PostingList) BuildSkips(placement SkipPlacement) {
+	m.skipLevels = nil
+	for _, distance := range placement(m.num) {
+		if distance < 2 || distance >= m.num {
+			continue
+		}
+		level := skipLevel{distance: distance, ids: make([]int64, 0, m.num/distance)}
+		for pos := distance; pos < m.num; pos += distance {
+			level.ids = append(level.ids, m.docIDList[pos])
+		}
+		m.skipLevels = append(m.skipLevels, level)
+	}
+}
+
+func (m *PostingList) HasSkips() bool {
+	return len(m.skipLevels) > 0
+}
+
+// SkipDistances returns the skip distances from the coarsest level to the
+// finest one.
+func (m *PostingList) SkipDistances() (distances []int) {
+	for _, level := range m.skipLevels {
+		distances = append(distances, level.distance)
+	}
+	return
+}
+
+// SkipTo returns the first position at or after from whose docID is not
+// smaller than id, or Size() if there is none. The skip levels are used from
+// the coarsest to the finest one before scanning linearly.
+func (m *PostingList) SkipTo(id int64, from int) int {
+	pos := from
+	for _, level := range m.skipLevels {
+		// level.ids[j] is the docID at position (j+1)*distance
+		for j := pos / level.distance; j < len(level.ids) && level.ids[j] < id; j++ {
+			pos = (j + 1) * level.distance
+		}
+	}
+	for pos < m.num && m.docIDList[pos] < id {
+		pos++
+	}
+	return pos
+}
@@ -0,0 +1,52 @@
+package postinglist
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostingList_SkipTo(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 10000
+	l := NewPostingList()
+	l.Reserve(n)
+	var id int64
+	for i := 0; i < n; i++ {
+		id += 1 + int64(r.Intn(10))
+		l.AddPosting(id, 1)
+	}
+
+	tests := []struct {
+		givenPlacement SkipPlacement
+		wantDistances  []int
+	}{
+		{SqrtSkips(), []int{100}},
+		{FixedSkips(7), []int{7}},
+		{MultiLevelSkips(8, 3), []int{512, 64, 8}},
+		{MultiLevelSkips(8, 10), []int{4096, 512, 64, 8}},
+	}
+
+	for _, tt := range tests {
+		l.BuildSkips(tt.givenPlacement)
+		assert.Equal(t, tt.wantDistances, l.SkipDistances())
+
+		for i := 0; i < 1000; i++ {
+			from, target := r.Intn(n), 1+r.Int63n(id+10)
+			want := from
+			for want < n && l.GetId(want) < target {
+				want++
+			}
+			assert.Equal(t, want, l.SkipTo(target, from))
+		}
+	}
+}
+
+func TestPostingList_ReadFromFileWithSkipPointer(t *testing.T) {
+	l := NewPostingList()
+	assert.NoError(t, l.ReadFromFileWithSkipPointer("../data/example3.txt", 2000))
+	assert.False(t, l.HasSkips())
+	assert.Equal(t, 1, l.SkipTo(6, 0))
+	assert.Equal(t, 2, l.SkipTo(8, 0))
+}
@@ -0,0 +1,113 @@
+package roaring
+
+import (
+	"testing"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/intersection"
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareData() (postingLists []*PostingList, err error) {
+	l1, l2, l3 := NewPostingList(), NewPostingList(), NewPostingList()
+
+	if err = l1.ReadFromFile("../data/bowling.txt"); err != nil {
+		return
+	}
+
+	if err = l2.ReadFromFile("../data/film.txt"); err != nil {
+		return
+	}
+
+	if err = l3.ReadFromFile("../data/rug.txt"); err != nil {
+		return
+	}
+
+	postingLists = append(postingLists, l1, l2, l3)
+	return
+}
+
+func BenchmarkIntersectHybrid(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(postingLists); j++ {
+			for k := 0; k < j; k++ {
+				intersection.IntersectHybrid(postingLists[j], postingLists[k])
+			}
+		}
+	}
+}
+
+func benchmarkIntersectRoaring(b *testing.B, policy Policy) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	var sets []*Roaring
+	for _, l := range postingLists {
+		sets = append(sets, NewRoaringFromPostingList(l, policy))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(sets); j++ {
+			for k := 0; k < j; k++ {
+				IntersectRoaring(sets[j], sets[k])
+			}
+		}
+	}
+}
+
+func BenchmarkIntersectRoaringDensity(b *testing.B) {
+	benchmarkIntersectRoaring(b, DensityPolicy)
+}
+
+func BenchmarkIntersectRoaringArray(b *testing.B) {
+	benchmarkIntersectRoaring(b, ArrayPolicy)
+}
+
+func BenchmarkIntersectRoaringBitmap(b *testing.B) {
+	benchmarkIntersectRoaring(b, BitmapPolicy)
+}
+
+func BenchmarkIntersectBitmap(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	var sets []*Bitmap
+	for _, l := range postingLists {
+		sets = append(sets, NewBitmapFromPostingList(l))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(sets); j++ {
+			for k := 0; k < j; k++ {
+				IntersectBitmap(sets[j], sets[k])
+			}
+		}
+	}
+}
+
+// BenchmarkIntersectPostingListWithRoaring keeps the scores of the smaller list
+// by probing a Roaring set of the larger one.
+func BenchmarkIntersectPostingListWithRoaring(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	var sets []*Roaring
+	for _, l := range postingLists {
+		sets = append(sets, NewRoaringFromPostingList(l, DensityPolicy))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(sets); j++ {
+			for k := 0; k < j; k++ {
+				IntersectPostingList(postingLists[k], sets[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package roaring
+
+import (
+	"math/bits"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+// Bitmap is a flat bitmap with one bit per docID from 0 up to the largest
+// docID. It only pays off for very dense lists.
+type Bitmap struct {
+	words []uint64
+	card  int
+}
+
+func NewBitmap() *Bitmap {
+	return &Bitmap{}
+}
+
+// NewBitmapFromPostingList builds a bitmap of the non-negative docIDs in l.
+// Scores are not kept.
+func NewBitmapFromPostingList(l *PostingList) *Bitmap {
+	b := NewBitmap()
+	if l.Size() > 0 {
+		b.words = make([]uint64, l.GetId(l.Size()-1)/64+1)
+	}
+	l.Iterate(func(i int) {
+		b.Add(l.GetId(i))
+	})
+	return b
+}
+
+// Add adds the docID, which must not be negative, the bitmap grows up to it.
+func (b *Bitmap) Add(id int64) {
+	w := int(id >> 6)
+	if w >= len(b.words) {
+		words := make([]uint64, w+1)
+		copy(words, b.words)
+		b.words = words
+	}
+	mask := uint64(1) << uint(id&63)
+	if b.words[w]&mask == 0 {
+		b.words[w] |= mask
+		b.card++
+	}
+}
+
+func (b *Bitmap) Cardinality() int {
+	return b.card
+}
+
+func (b *Bitmap) Contains(id int64) bool {
+	w := int(id >> 6)
+	return id >= 0 && w < len(b.words) && b.words[w]&(1<<uint(id&63)) != 0
+}
+
+// Iterate calls iterator with every docID in increasing order.
+func (b *Bitmap) Iterate(iterator func(id int64)) {
+	for i, w := range b.words {
+		for w != 0 {
+			t := bits.TrailingZeros64(w)
+			iterator(int64(i*64 + t))
+			w &= w - 1
+		}
+	}
+}
+
+func IntersectBitmap(b1, b2 *Bitmap) (ret *Bitmap) {
+	if len(b1.words) > len(b2.words) {
+		b1, b2 = b2, b1
+	}
+	ret = &Bitmap{words: make([]uint64, len(b1.words))}
+	for i := range ret.words {
+		ret.words[i] = b1.words[i] & b2.words[i]
+		ret.card += bits.OnesCount64(ret.words[i])
+	}
+	return
+}
+
+func UnionBitmap(b1, b2 *Bitmap) (ret *Bitmap) {
+	if len(b1.words) < len(b2.words) {
+		b1, b2 = b2, b1
+	}
+	ret = &Bitmap{words: make([]uint64, len(b1.words))}
+	copy(ret.words, b1.words)
+	for i := range b2.words {
+		ret.words[i] |= b2.words[i]
+	}
+	for _, w := range ret.words {
+		ret.card += bits.OnesCount64(w)
+	}
+	return
+}
@@ -0,0 +1,370 @@
+package roaring
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	chunkSize       = 1 << 16
+	bitmapWords     = chunkSize / 64
+	arrayMaxSize    = 4096
+	bitmapSizeBytes = chunkSize / 8
+)
+
+type ContainerKind int
+
+const (
+	ArrayContainer ContainerKind = iota
+	BitmapContainer
+	RunContainer
+)
+
+func (k ContainerKind) String() string {
+	switch k {
+	case ArrayContainer:
+		return "array"
+	case BitmapContainer:
+		return "bitmap"
+	case RunContainer:
+		return "run"
+	default:
+		return "unknown"
+	}
+}
+
+// container holds the lower 16 bits of all docIDs sharing the same upper bits.
+type container interface {
+	kind() ContainerKind
+	cardinality() int
+	numRuns() int
+	contains(x uint16) bool
+	iterate(f func(x uint16))
+}
+
+type arrayContainer struct {
+	values []uint16
+}
+
+func (c *arrayContainer) kind() ContainerKind { return ArrayContainer }
+
+func (c *arrayContainer) cardinality() int { return len(c.values) }
+
+func (c *arrayContainer) numRuns() int {
+	if len(c.values) == 0 {
+		return 0
+	}
+	n := 1
+	for i := 1; i < len(c.values); i++ {
+		if c.values[i] != c.values[i-1]+1 {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *arrayContainer) contains(x uint16) bool {
+	i := sort.Search(len(c.values), func(i int) bool { return c.values[i] >= x })
+	return i < len(c.values) && c.values[i] == x
+}
+
+func (c *arrayContainer) iterate(f func(x uint16)) {
+	for _, v := range c.values {
+		f(v)
+	}
+}
+
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (c *bitmapContainer) kind() ContainerKind { return BitmapContainer }
+
+func (c *bitmapContainer) cardinality() int { return c.card }
+
+func (c *bitmapContainer) numRuns() int {
+	n := 0
+	for i, w := range c.words {
+		// count the positions where a run starts: set bit whose predecessor is unset
+		var carry uint64
+		if i > 0 {
+			carry = c.words[i-1] >> 63
+		}
+		n += bits.OnesCount64(w &^ (w<<1 | carry))
+	}
+	return n
+}
+
+func (c *bitmapContainer) contains(x uint16) bool {
+	return c.words[x>>6]&(1<<(x&63)) != 0
+}
+
+func (c *bitmapContainer) add(x uint16) {
+	mask := uint64(1) << (x & 63)
+	if c.words[x>>6]&mask == 0 {
+		c.words[x>>6] |= mask
+		c.card++
+	}
+}
+
+func (c *bitmapContainer) iterate(f func(x uint16)) {
+	for i, w := range c.words {
+		for w != 0 {
+			t := bits.TrailingZeros64(w)
+			f(uint16(i*64 + t))
+			w &= w - 1
+		}
+	}
+}
+
+// interval is an inclusive range [start, last] of consecutive values.
+type interval struct {
+	start, last uint16
+}
+
+type runContainer struct {
+	runs []interval
+}
+
+func (c *runContainer) kind() ContainerKind { return RunContainer }
+
+func (c *runContainer) cardinality() int {
+	n := 0
+	for _, r := range c.runs {
+		n += int(r.last-r.start) + 1
+	}
+	return n
+}
+
+func (c *runContainer) numRuns() int { return len(c.runs) }
+
+func (c *runContainer) contains(x uint16) bool {
+	i := sort.Search(len(c.runs), func(i int) bool { return c.runs[i].last >= x })
+	return i < len(c.runs) && c.runs[i].start <= x
+}
+
+func (c *runContainer) iterate(f func(x uint16)) {
+	for _, r := range c.runs {
+		for v := int(r.start); v <= int(r.last); v++ {
+			f(uint16(v))
+		}
+	}
+}
+
+func newArrayContainer(values []uint16) *arrayContainer {
+	return &arrayContainer{values: values}
+}
+
+func newBitmapContainer(values []uint16) *bitmapContainer {
+	c := &bitmapContainer{}
+	for _, v := range values {
+		c.add(v)
+	}
+	return c
+}
+
+func newRunContainer(values []uint16) *runContainer {
+	c := &runContainer{}
+	for i, v := range values {
+		if i > 0 && v == c.runs[len(c.runs)-1].last+1 {
+			c.runs[len(c.runs)-1].last = v
+		} else {
+			c.runs = append(c.runs, interval{v, v})
+		}
+	}
+	return c
+}
+
+func containerValues(c container) []uint16 {
+	if a, ok := c.(*arrayContainer); ok {
+		return a.values
+	}
+	values := make([]uint16, 0, c.cardinality())
+	c.iterate(func(x uint16) {
+		values = append(values, x)
+	})
+	return values
+}
+
+// buildContainer converts the sorted values into the representation chosen
+// by the given policy.
+func buildContainer(values []uint16, policy Policy) container {
+	runs := 0
+	for i := range values {
+		if i == 0 || values[i] != values[i-1]+1 {
+			runs++
+		}
+	}
+	switch policy(len(values), runs) {
+	case BitmapContainer:
+		return newBitmapContainer(values)
+	case RunContainer:
+		return newRunContainer(values)
+	default:
+		return newArrayContainer(values)
+	}
+}
+
+func andContainers(a, b container, policy Policy) container {
+	if a.cardinality() > b.cardinality() {
+		a, b = b, a
+	}
+
+	switch ca := a.(type) {
+	case *bitmapContainer:
+		// word by word if b is a bitmap too
+		if cb, ok := b.(*bitmapContainer); ok {
+			ret := &bitmapContainer{}
+			for i := range ret.words {
+				ret.words[i] = ca.words[i] & cb.words[i]
+				ret.card += bits.OnesCount64(ret.words[i])
+			}
+			return optimize(ret, policy)
+		}
+	case *runContainer:
+		if cb, ok := b.(*runContainer); ok {
+			return optimize(andRuns(ca, cb), policy)
+		}
+	case *arrayContainer:
+		if cb, ok := b.(*arrayContainer); ok {
+			return optimize(newArrayContainer(andArrays(ca.values, cb.values)), policy)
+		}
+	}
+
+	// mixed representations: probe the larger container with every value of
+	// the smaller one
+	var values []uint16
+	a.iterate(func(x uint16) {
+		if b.contains(x) {
+			values = append(values, x)
+		}
+	})
+	return buildContainer(values, policy)
+}
+
+func andArrays(a, b []uint16) (ret []uint16) {
+	var i, j int
+	for i < len(a) && j < len(b) {
+		if a[i] < b[j] {
+			i++
+		} else if b[j] < a[i] {
+			j++
+		} else {
+			ret = append(ret, a[i])
+			i++
+			j++
+		}
+	}
+	return
+}
+
+func andRuns(a, b *runContainer) *runContainer {
+	ret := &runContainer{}
+	var i, j int
+	for i < len(a.runs) && j < len(b.runs) {
+		ra, rb := a.runs[i], b.runs[j]
+		start, last := ra.start, ra.last
+		if rb.start > start {
+			start = rb.start
+		}
+		if rb.last < last {
+			last = rb.last
+		}
+		if start <= last {
+			ret.runs = append(ret.runs, interval{start, last})
+		}
+		if ra.last < rb.last {
+			i++
+		} else {
+			j++
+		}
+	}
+	return ret
+}
+
+func orContainers(a, b container, policy Policy) container {
+	ca, aIsArray := a.(*arrayContainer)
+	cb, bIsArray := b.(*arrayContainer)
+	if aIsArray && bIsArray {
+		return buildContainer(orArrays(ca.values, cb.values), policy)
+	}
+
+	if ra, ok := a.(*runContainer); ok {
+		if rb, ok := b.(*runContainer); ok {
+			return optimize(orRuns(ra, rb), policy)
+		}
+	}
+
+	ret := &bitmapContainer{}
+	for _, c := range []container{a, b} {
+		if bc, ok := c.(*bitmapContainer); ok {
+			for i := range ret.words {
+				ret.words[i] |= bc.words[i]
+			}
+		} else {
+			c.iterate(func(x uint16) {
+				ret.words[x>>6] |= 1 << (x & 63)
+			})
+		}
+	}
+	for _, w := range ret.words {
+		ret.card += bits.OnesCount64(w)
+	}
+	return optimize(ret, policy)
+}
+
+func orArrays(a, b []uint16) []uint16 {
+	ret := make([]uint16, 0, len(a)+len(b))
+	var i, j int
+	for i < len(a) && j < len(b) {
+		if a[i] < b[j] {
+			ret = append(ret, a[i])
+			i++
+		} else if b[j] < a[i] {
+			ret = append(ret, b[j])
+			j++
+		} else {
+			ret = append(ret, a[i])
+			i++
+			j++
+		}
+	}
+	ret = append(ret, a[i:]...)
+	ret = append(ret, b[j:]...)
+	return ret
+}
+
+func orRuns(a, b *runContainer) *runContainer {
+	ret := &runContainer{}
+	push := func(r interval) {
+		n := len(ret.runs)
+		if n > 0 && int(r.start) <= int(ret.runs[n-1].last)+1 {
+			if r.last > ret.runs[n-1].last {
+				ret.runs[n-1].last = r.last
+			}
+			return
+		}
+		ret.runs = append(ret.runs, r)
+	}
+
+	var i, j int
+	for i < len(a.runs) || j < len(b.runs) {
+		if j == len(b.runs) || (i < len(a.runs) && a.runs[i].start <= b.runs[j].start) {
+			push(a.runs[i])
+			i++
+		} else {
+			push(b.runs[j])
+			j++
+		}
+	}
+	return ret
+}
+
+// optimize re-encodes c if the policy prefers another representation.
+func optimize(c container, policy Policy) container {
+	if policy(c.cardinality(), c.numRuns()) == c.kind() {
+		return c
+	}
+	return buildContainer(containerValues(c), policy)
+}
@@ -0,0 +1,30 @@
+package roaring
+
+// Policy picks the representation of a single 2^16 chunk given the number of
+// docIDs in it and the number of runs of consecutive docIDs they form.
+type Policy func(cardinality, numRuns int) ContainerKind
+
+// DensityPolicy picks the smallest representation, as Roaring does: an array
+// costs 2 bytes per docID, a bitmap always costs 8KB and a run container costs
+// 4 bytes per run. Sparse chunks thus stay arrays, dense chunks become bitmaps
+// and chunks made of long ranges become runs.
+func DensityPolicy(cardinality, numRuns int) ContainerKind {
+	arrayBytes, runBytes := 2*cardinality, 4*numRuns
+	if runBytes < arrayBytes && runBytes < bitmapSizeBytes {
+		return RunContainer
+	}
+	if cardinality > arrayMaxSize {
+		return BitmapContainer
+	}
+	return ArrayContainer
+}
+
+// ArrayPolicy always uses sorted arrays, it is useful as a baseline.
+func ArrayPolicy(int, int) ContainerKind {
+	return ArrayContainer
+}
+
+// BitmapPolicy always uses bitmaps, it is useful as a baseline.
+func BitmapPolicy(int, int) ContainerKind {
+	return BitmapContainer
+}
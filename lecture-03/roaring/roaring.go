@@ -0,0 +1,142 @@
+package roaring
+
+import (
+	"sort"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+// Roaring splits the docID space into chunks of 2^16 ids, and stores the lower
+// 16 bits of the docIDs in each non-empty chunk in an array, bitmap or run
+// container.
+type Roaring struct {
+	keys       []int64
+	containers []container
+	policy     Policy
+}
+
+func NewRoaring(policy Policy) *Roaring {
+	if policy == nil {
+		policy = DensityPolicy
+	}
+	return &Roaring{policy: policy}
+}
+
+// NewRoaringFromPostingList builds a Roaring set of the docIDs in l. Scores are
+// not kept.
+func NewRoaringFromPostingList(l *PostingList, policy Policy) *Roaring {
+	ids := make([]int64, l.Size())
+	l.Iterate(func(i int) {
+		ids[i] = l.GetId(i)
+	})
+	return NewRoaringFromSortedIds(ids, policy)
+}
+
+// NewRoaringFromSortedIds builds a Roaring set from non-negative docIDs in
+// increasing order.
+func NewRoaringFromSortedIds(ids []int64, policy Policy) *Roaring {
+	r := NewRoaring(policy)
+	var values []uint16
+	for i, id := range ids {
+		key := id >> 16
+		if i > 0 && key != ids[i-1]>>16 {
+			r.appendChunk(ids[i-1]>>16, values)
+			values = nil
+		}
+		values = append(values, uint16(id))
+	}
+	if len(values) > 0 {
+		r.appendChunk(ids[len(ids)-1]>>16, values)
+	}
+	return r
+}
+
+func (r *Roaring) appendChunk(key int64, values []uint16) {
+	r.keys = append(r.keys, key)
+	r.containers = append(r.containers, buildContainer(values, r.policy))
+}
+
+func (r *Roaring) appendContainer(key int64, c container) {
+	if c.cardinality() == 0 {
+		return
+	}
+	r.keys = append(r.keys, key)
+	r.containers = append(r.containers, c)
+}
+
+func (r *Roaring) Cardinality() int {
+	n := 0
+	for _, c := range r.containers {
+		n += c.cardinality()
+	}
+	return n
+}
+
+func (r *Roaring) Contains(id int64) bool {
+	key := id >> 16
+	i := sort.Search(len(r.keys), func(i int) bool { return r.keys[i] >= key })
+	return i < len(r.keys) && r.keys[i] == key && r.containers[i].contains(uint16(id))
+}
+
+// Iterate calls iterator with every docID in increasing order.
+func (r *Roaring) Iterate(iterator func(id int64)) {
+	for i, c := range r.containers {
+		base := r.keys[i] << 16
+		c.iterate(func(x uint16) {
+			iterator(base | int64(x))
+		})
+	}
+}
+
+// Stats returns the number of containers of each kind.
+func (r *Roaring) Stats() map[ContainerKind]int {
+	stats := make(map[ContainerKind]int)
+	for _, c := range r.containers {
+		stats[c.kind()]++
+	}
+	return stats
+}
+
+// IntersectRoaring intersects two Roaring sets chunk by chunk, the containers
+// of the two sets may use different representations. The result uses the
+// policy of r1.
+func IntersectRoaring(r1, r2 *Roaring) (ret *Roaring) {
+	ret = NewRoaring(r1.policy)
+
+	var i1, i2 int
+	for i1 < len(r1.keys) && i2 < len(r2.keys) {
+		k1, k2 := r1.keys[i1], r2.keys[i2]
+		if k1 < k2 {
+			i1++
+		} else if k2 < k1 {
+			i2++
+		} else {
+			ret.appendContainer(k1, andContainers(r1.containers[i1], r2.containers[i2], ret.policy))
+			i1++
+			i2++
+		}
+	}
+	return
+}
+
+// UnionRoaring merges two Roaring sets chunk by chunk. The result uses the
+// policy of r1.
+func UnionRoaring(r1, r2 *Roaring) (ret *Roaring) {
+	ret = NewRoaring(r1.policy)
+
+	var i1, i2 int
+	for i1 < len(r1.keys) || i2 < len(r2.keys) {
+		if i2 == len(r2.keys) || (i1 < len(r1.keys) && r1.keys[i1] < r2.keys[i2]) {
+			ret.appendContainer(r1.keys[i1], r1.containers[i1])
+			i1++
+		} else if i1 == len(r1.keys) || r2.keys[i2] < r1.keys[i1] {
+			ret.appendContainer(r2.keys[i2], r2.containers[i2])
+			i2++
+		} else {
+			ret.appendContainer(r1.keys[i1], orContainers(r1.containers[i1], r2.containers[i2], ret.policy))
+			i1++
+			i2++
+		}
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package roaring
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/intersection"
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+// randomPostingList mixes sparse, dense and run-like regions so that every
+// container kind shows up under DensityPolicy.
+func randomPostingList(r *rand.Rand) *PostingList {
+	var ids []int64
+	for id := int64(0); id < 5*chunkSize; id++ {
+		chunk := id >> 16
+		switch {
+		case chunk == 0 && r.Intn(100) == 0:
+			ids = append(ids, id)
+		case chunk == 1 && r.Intn(3) == 0:
+			ids = append(ids, id)
+		case chunk == 2 && (id/1000)%2 == 0:
+			ids = append(ids, id)
+		case chunk >= 3 && r.Intn(20) == 0:
+			ids = append(ids, id)
+		}
+	}
+
+	l := NewPostingList()
+	l.Reserve(len(ids))
+	for _, id := range ids {
+		l.AddPosting(id, 1)
+	}
+	return l
+}
+
+func ids(s DocIDSet) []int64 {
+	return collect(s)
+}
+
+func postingListIds(l *PostingList) []int64 {
+	ret := make([]int64, 0, l.Size())
+	l.Iterate(func(i int) {
+		ret = append(ret, l.GetId(i))
+	})
+	return ret
+}
+
+func TestRoaring_Policies(t *testing.T) {
+	l := randomPostingList(rand.New(rand.NewSource(1)))
+	r := NewRoaringFromPostingList(l, DensityPolicy)
+
+	assert.Equal(t, l.Size(), r.Cardinality())
+	assert.Equal(t, postingListIds(l), ids(r))
+	assert.True(t, r.Contains(l.GetId(l.Size()-1)))
+	assert.False(t, r.Contains(133500))
+
+	stats := r.Stats()
+	assert.Equal(t, 3, stats[ArrayContainer])
+	assert.Equal(t, 1, stats[BitmapContainer])
+	assert.Equal(t, 1, stats[RunContainer])
+}
+
+func TestIntersectAndUnion(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	l1, l2 := randomPostingList(rnd), randomPostingList(rnd)
+
+	wantIntersection := postingListIds(intersection.IntersectBasic(l1, l2))
+	wantUnion := ids(Union(NewRoaringFromPostingList(l1, ArrayPolicy), NewRoaringFromPostingList(l2, ArrayPolicy)))
+
+	policies := []Policy{DensityPolicy, ArrayPolicy, BitmapPolicy}
+	for _, p1 := range policies {
+		for _, p2 := range policies {
+			r1, r2 := NewRoaringFromPostingList(l1, p1), NewRoaringFromPostingList(l2, p2)
+			assert.Equal(t, wantIntersection, ids(IntersectRoaring(r1, r2)))
+			assert.Equal(t, wantUnion, ids(UnionRoaring(r1, r2)))
+		}
+	}
+
+	b1, b2 := NewBitmapFromPostingList(l1), NewBitmapFromPostingList(l2)
+	assert.Equal(t, wantIntersection, ids(IntersectBitmap(b1, b2)))
+	assert.Equal(t, wantUnion, ids(UnionBitmap(b1, b2)))
+
+	r2 := NewRoaringFromPostingList(l2, DensityPolicy)
+	assert.Equal(t, wantIntersection, ids(Intersect(b1, r2)))
+	assert.Equal(t, wantUnion, ids(Union(b1, r2)))
+	assert.Equal(t, wantIntersection, postingListIds(IntersectPostingList(l1, r2)))
+}
+
+func TestIntersectPostingList(t *testing.T) {
+	l1, l2 := NewPostingList(), NewPostingList()
+	assert.NoError(t, l1.ReadFromFile("../data/example1.txt"))
+	assert.NoError(t, l2.ReadFromFile("../data/example2.txt"))
+
+	ret := IntersectPostingList(l1, NewBitmapFromPostingList(l2))
+	assert.Equal(t, "[(2, 5), (6, 2)]", ret.String())
+}
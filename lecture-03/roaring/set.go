@@ -0,0 +1,96 @@
+package roaring
+
+import (
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+// DocIDSet is implemented by every docID set representation in this package,
+// it lets sets of different representations be combined.
+type DocIDSet interface {
+	Cardinality() int
+	Contains(id int64) bool
+	Iterate(iterator func(id int64))
+}
+
+// Intersect intersects two sets of any representation. Two Roaring sets or two
+// Bitmaps use the specialized algorithms, otherwise the smaller set is probed
+// against the larger one. The result is a Roaring set with DensityPolicy.
+func Intersect(s1, s2 DocIDSet) *Roaring {
+	if r1, ok := s1.(*Roaring); ok {
+		if r2, ok := s2.(*Roaring); ok {
+			return IntersectRoaring(r1, r2)
+		}
+	}
+	if b1, ok := s1.(*Bitmap); ok {
+		if b2, ok := s2.(*Bitmap); ok {
+			return NewRoaringFromSortedIds(collect(IntersectBitmap(b1, b2)), DensityPolicy)
+		}
+	}
+
+	if s1.Cardinality() > s2.Cardinality() {
+		s1, s2 = s2, s1
+	}
+	var ids []int64
+	s1.Iterate(func(id int64) {
+		if s2.Contains(id) {
+			ids = append(ids, id)
+		}
+	})
+	return NewRoaringFromSortedIds(ids, DensityPolicy)
+}
+
+// Union merges two sets of any representation. The result is a Roaring set
+// with DensityPolicy.
+func Union(s1, s2 DocIDSet) *Roaring {
+	if r1, ok := s1.(*Roaring); ok {
+		if r2, ok := s2.(*Roaring); ok {
+			return UnionRoaring(r1, r2)
+		}
+	}
+
+	ids1, ids2 := collect(s1), collect(s2)
+	ids := make([]int64, 0, len(ids1)+len(ids2))
+	var i1, i2 int
+	for i1 < len(ids1) && i2 < len(ids2) {
+		if ids1[i1] < ids2[i2] {
+			ids = append(ids, ids1[i1])
+			i1++
+		} else if ids2[i2] < ids1[i1] {
+			ids = append(ids, ids2[i2])
+			i2++
+		} else {
+			ids = append(ids, ids1[i1])
+			i1++
+			i2++
+		}
+	}
+	ids = append(ids, ids1[i1:]...)
+	ids = append(ids, ids2[i2:]...)
+	return NewRoaringFromSortedIds(ids, DensityPolicy)
+}
+
+// IntersectPostingList keeps the postings of l whose docID is in s, so the
+// scores of l survive the intersection with a set that has none.
+func IntersectPostingList(l *PostingList, s DocIDSet) (ret *PostingList) {
+	ret = NewPostingList()
+	minSize := l.Size()
+	if s.Cardinality() < minSize {
+		minSize = s.Cardinality()
+	}
+	ret.Reserve(minSize)
+
+	l.Iterate(func(i int) {
+		if ret.Size() < minSize && s.Contains(l.GetId(i)) {
+			ret.AddPosting(l.GetId(i), l.GetScore(i))
+		}
+	})
+	return
+}
+
+func collect(s DocIDSet) []int64 {
+	ids := make([]int64, 0, s.Cardinality())
+	s.Iterate(func(id int64) {
+		ids = append(ids, id)
+	})
+	return ids
+}
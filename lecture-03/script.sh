@@ -1,3 +1,11 @@
 # find out the intersection of all three dataset
 go run cmd/intersection_of_all/main.go | sort -k2,2rn
 # 29782 	 1377
+
+# convert a posting list to the binary format
+go run cmd/convert_to_binary/main.go data/bowling.txt data/bowling.bin
+
+# calibrate the cost model of IntersectHybrid, the constants of DefaultCostModel
+# are this output, go1.27 on a single core Intel Xeon (linux/amd64)
+go run cmd/calibrate/main.go cost_model.json
+# {LinearMerge:5.151607207423684 GallopingSearch:9.70813061975972 BinarySearch:1.8905644374183976 SkipPointer:4.667943375601972}
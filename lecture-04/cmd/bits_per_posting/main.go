@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-04/compression"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: cmd <posting list file>...")
+		os.Exit(-1)
+	}
+
+	codecs := compression.AllCodecs()
+
+	fmt.Printf("| list | #postings |")
+	for _, codec := range codecs {
+		fmt.Printf(" %s |", codec.Name())
+	}
+	fmt.Printf("\n|------|-----------|")
+	for range codecs {
+		fmt.Printf("------|")
+	}
+	fmt.Println()
+
+	for _, filename := range os.Args[1:] {
+		l := NewPostingList()
+		if err := l.ReadFromFile(filename); err != nil {
+			fmt.Printf("ReadFromFile err %v\n", err)
+			os.Exit(-1)
+		}
+
+		fmt.Printf("| %s | %d |", filepath.Base(filename), l.Size())
+		for _, codec := range codecs {
+			bits, err := compression.BitsPerPosting(codec, l)
+			if err != nil {
+				fmt.Printf("BitsPerPosting err %v\n", err)
+				os.Exit(-1)
+			}
+			fmt.Printf(" %.2f |", bits)
+		}
+		fmt.Println()
+	}
+}
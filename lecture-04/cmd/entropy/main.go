@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-04/compression"
+)
+
+// For each posting list file, print the most frequent gaps, the entropy of the
+// empirical gap distribution and how far each codec is from it.
+func main() {
+	top := flag.Int("top", 10, "number of most frequent gaps to show")
+	flag.Usage = func() {
+		fmt.Println("Usage: cmd [-top n] <posting list file>...")
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(-1)
+	}
+
+	for _, filename := range flag.Args() {
+		l := NewPostingList()
+		if err := l.ReadFromFile(filename); err != nil {
+			fmt.Printf("ReadFromFile err %v\n", err)
+			os.Exit(-1)
+		}
+		gaps, err := compression.GapEncode(l.DocIDs())
+		if err != nil {
+			fmt.Printf("GapEncode err %v\n", err)
+			os.Exit(-1)
+		}
+
+		distribution := compression.Distribution(gaps)
+		entropy := compression.Entropy(distribution)
+		fmt.Printf("## %s\n\n", filepath.Base(filename))
+		fmt.Printf("#gaps: %d, #distinct gaps: %d, entropy: %.3f bits/gap\n\n", len(gaps), len(distribution), entropy)
+
+		fmt.Println("| gap | count | p |")
+		fmt.Println("|-----|-------|---|")
+		for i, sc := range distribution {
+			if i >= *top {
+				break
+			}
+			fmt.Printf("| %d | %d | %.4f |\n", sc.Symbol, sc.Count, float64(sc.Count)/float64(len(gaps)))
+		}
+		fmt.Println()
+
+		fmt.Println("| codec | bits/gap | bits/gap - entropy | compression ratio |")
+		fmt.Println("|-------|----------|--------------------|-------------------|")
+		for _, codec := range compression.AllCodecs() {
+			data, err := codec.Encode(gaps)
+			if err != nil {
+				fmt.Printf("| %s | %v | | |\n", codec.Name(), err)
+				continue
+			}
+			bits := float64(8*len(data)) / float64(len(gaps))
+			// compared to the 64-bit docIDs of PostingList
+			fmt.Printf("| %s | %.3f | %+.3f | %.2f |\n", codec.Name(), bits, bits-entropy, 64/bits)
+		}
+		fmt.Println()
+	}
+}
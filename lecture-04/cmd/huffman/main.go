@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-04/compression"
+)
+
+// Builds a canonical Huffman code for the words of a word frequency file, as
+// written by lecture-01/cmd/wordfreq (one "word\tfrequency" per line). The
+// symbol of a word is its line number.
+func main() {
+	top := flag.Int("top", 10, "number of words to show with their codes")
+	output := flag.String("o", "", "write the serialized codebook to this file")
+	flag.Usage = func() {
+		fmt.Println("Usage: cmd [-top n] [-o codebook] <word frequency file>")
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(-1)
+	}
+
+	words, counts, err := readWordFrequencies(flag.Arg(0))
+	if err != nil {
+		fmt.Printf("readWordFrequencies err %v\n", err)
+		os.Exit(-1)
+	}
+
+	code, err := compression.NewHuffmanCode(counts)
+	if err != nil {
+		fmt.Printf("NewHuffmanCode err %v\n", err)
+		os.Exit(-1)
+	}
+
+	var distribution []compression.SymbolCount
+	for symbol, count := range counts {
+		distribution = append(distribution, compression.SymbolCount{Symbol: symbol, Count: int(count)})
+	}
+	fmt.Printf("#words: %d\n", len(words))
+	fmt.Printf("entropy: %.3f bits/word\n", compression.Entropy(distribution))
+	fmt.Printf("average code length: %.3f bits/word\n\n", code.AverageCodeLength(counts))
+
+	fmt.Println("| word | frequency | code |")
+	fmt.Println("|------|-----------|------|")
+	for i := 0; i < *top && i < len(words); i++ {
+		cw, _ := code.Codeword(uint64(i))
+		fmt.Printf("| %s | %d | %s |\n", words[i], counts[uint64(i)], cw)
+	}
+
+	if *output != "" {
+		codebook, _ := code.MarshalBinary()
+		if err = ioutil.WriteFile(*output, codebook, 0644); err != nil {
+			fmt.Printf("WriteFile err %v\n", err)
+			os.Exit(-1)
+		}
+	}
+}
+
+func readWordFrequencies(filename string) (words []string, counts map[uint64]uint64, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	counts = make(map[uint64]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), "\t")
+		if len(parts) != 2 {
+			err = fmt.Errorf("invalid line format: %q", scanner.Text())
+			return
+		}
+		var count uint64
+		if count, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 64); err != nil {
+			return
+		}
+		counts[uint64(len(words))] = count
+		words = append(words, parts[0])
+	}
+	err = scanner.Err()
+	return
+}
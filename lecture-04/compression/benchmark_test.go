@@ -0,0 +1,133 @@
+package compression
+
+import (
+	"testing"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/intersection"
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareData() (postingLists []*PostingList, err error) {
+	l1, l2, l3 := NewPostingList(), NewPostingList(), NewPostingList()
+
+	if err = l1.ReadFromFile("../../lecture-03/data/bowling.txt"); err != nil {
+		return
+	}
+
+	if err = l2.ReadFromFile("../../lecture-03/data/film.txt"); err != nil {
+		return
+	}
+
+	if err = l3.ReadFromFile("../../lecture-03/data/rug.txt"); err != nil {
+		return
+	}
+
+	postingLists = append(postingLists, l1, l2, l3)
+	return
+}
+
+func BenchmarkIntersectUncompressed(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	var size int
+	for _, l := range postingLists {
+		size += 16 * l.Size()
+	}
+	b.ReportMetric(float64(size), "bytes")
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(postingLists); j++ {
+			for k := 0; k < j; k++ {
+				intersection.IntersectWithSkipTo(postingLists[j], postingLists[k])
+			}
+		}
+	}
+}
+
+func BenchmarkIntersectBlockVB(b *testing.B) {
+	postingLists, err := prepareData()
+	assert.NoError(b, err)
+
+	var lists []*BlockVBPostingList
+	var size int
+	for _, l := range postingLists {
+		c := NewBlockVBPostingList(l, DefaultBlockSize)
+		lists = append(lists, c)
+		size += c.SizeInBytes()
+	}
+	b.ReportMetric(float64(size), "bytes")
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(lists); j++ {
+			for k := 0; k < j; k++ {
+				intersection.IntersectWithSkipTo(lists[j], lists[k])
+			}
+		}
+	}
+}
+
+func benchmarkDecode(b *testing.B, codec Codec) {
+	for _, name := range []string{"bowling", "film", "rug"} {
+		l := NewPostingList()
+		if err := l.ReadFromFile("../../lecture-03/data/" + name + ".txt"); err != nil {
+			b.Logf("skip %s: %v", name, err)
+			continue
+		}
+
+		gaps, err := GapEncode(l.DocIDs())
+		assert.NoError(b, err)
+		scores := make([]uint64, l.Size())
+		for i, score := range l.Scores() {
+			scores[i] = uint64(score)
+		}
+
+		for _, values := range []struct {
+			name   string
+			values []uint64
+		}{{"gaps", gaps}, {"scores", scores}} {
+			data, err := codec.Encode(values.values)
+			assert.NoError(b, err)
+
+			b.Run(name+"/"+values.name, func(b *testing.B) {
+				// throughput in bytes of decoded 64-bit integers
+				b.SetBytes(int64(8 * len(values.values)))
+				b.ReportMetric(float64(8*len(data))/float64(len(values.values)), "bits/value")
+				for i := 0; i < b.N; i++ {
+					_, _ = codec.Decode(data, len(values.values))
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDecodeEliasGamma(b *testing.B) {
+	benchmarkDecode(b, EliasGamma)
+}
+
+func BenchmarkDecodeEliasDelta(b *testing.B) {
+	benchmarkDecode(b, EliasDelta)
+}
+
+func BenchmarkDecodeGolomb(b *testing.B) {
+	benchmarkDecode(b, NewGolombCodec(0))
+}
+
+func BenchmarkDecodeVariableByte(b *testing.B) {
+	benchmarkDecode(b, VariableByte)
+}
+
+func BenchmarkDecodePForDelta(b *testing.B) {
+	benchmarkDecode(b, PForDelta)
+}
+
+func BenchmarkDecodeSimple8b(b *testing.B) {
+	benchmarkDecode(b, Simple8b)
+}
+
+func BenchmarkDecodeRANS(b *testing.B) {
+	benchmarkDecode(b, NewRANSCodec(nil))
+}
@@ -0,0 +1,124 @@
+package compression
+
+import "errors"
+
+var ErrUnexpectedEnd = errors.New("unexpected end of bit stream")
+
+// BitWriter appends bits, most significant bit first, to a byte slice.
+type BitWriter struct {
+	buf   []byte
+	acc   uint64
+	nBits uint
+}
+
+func NewBitWriter() *BitWriter {
+	return &BitWriter{}
+}
+
+func (w *BitWriter) WriteBit(bit uint) {
+	w.WriteBits(uint64(bit&1), 1)
+}
+
+// WriteBits writes the n ≤ 64 lowest bits of v.
+func (w *BitWriter) WriteBits(v uint64, n uint) {
+	if n > 32 {
+		w.WriteBits(v>>32, n-32)
+		n = 32
+	}
+	if n < 64 {
+		v &= 1<<n - 1
+	}
+	w.acc = w.acc<<n | v
+	w.nBits += n
+	for w.nBits >= 8 {
+		w.nBits -= 8
+		w.buf = append(w.buf, byte(w.acc>>w.nBits))
+	}
+}
+
+// WriteUnary writes n zeros followed by a one.
+func (w *BitWriter) WriteUnary(n uint64) {
+	for ; n >= 32; n -= 32 {
+		w.WriteBits(0, 32)
+	}
+	w.WriteBits(1, uint(n)+1)
+}
+
+// Len returns the number of bits written so far.
+func (w *BitWriter) Len() int {
+	return 8*len(w.buf) + int(w.nBits)
+}
+
+// Bytes pads the last byte with zeros and returns the written bytes. The
+// writer must not be used afterwards.
+func (w *BitWriter) Bytes() []byte {
+	if w.nBits > 0 {
+		w.buf = append(w.buf, byte(w.acc<<(8-w.nBits)))
+		w.nBits = 0
+	}
+	return w.buf
+}
+
+// BitReader reads bits, most significant bit first, from a byte slice.
+type BitReader struct {
+	buf []byte
+	pos int
+}
+
+func NewBitReader(buf []byte) *BitReader {
+	return &BitReader{buf: buf}
+}
+
+func (r *BitReader) ReadBit() (uint, error) {
+	if r.pos >= 8*len(r.buf) {
+		return 0, ErrUnexpectedEnd
+	}
+	bit := uint(r.buf[r.pos>>3]>>(7-uint(r.pos&7))) & 1
+	r.pos++
+	return bit, nil
+}
+
+// ReadBits reads n ≤ 64 bits.
+func (r *BitReader) ReadBits(n uint) (v uint64, err error) {
+	if r.pos+int(n) > 8*len(r.buf) {
+		return 0, ErrUnexpectedEnd
+	}
+	for n > 0 {
+		// take as many bits as possible from the current byte
+		offset := uint(r.pos & 7)
+		take := 8 - offset
+		if take > n {
+			take = n
+		}
+		b := uint64(r.buf[r.pos>>3]>>(8-offset-take)) & (1<<take - 1)
+		v = v<<take | b
+		r.pos += int(take)
+		n -= take
+	}
+	return
+}
+
+// ReadUnary counts the zeros before the next one, and consumes the one.
+func (r *BitReader) ReadUnary() (n uint64, err error) {
+	for {
+		if r.pos >= 8*len(r.buf) {
+			return 0, ErrUnexpectedEnd
+		}
+		// skip whole zero bytes at once
+		if r.pos&7 == 0 && r.buf[r.pos>>3] == 0 {
+			n += 8
+			r.pos += 8
+			continue
+		}
+		bit, _ := r.ReadBit()
+		if bit == 1 {
+			return
+		}
+		n++
+	}
+}
+
+// Pos returns the number of bits read so far.
+func (r *BitReader) Pos() int {
+	return r.pos
+}
@@ -0,0 +1,47 @@
+package compression
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitWriterReader(t *testing.T) {
+	w := NewBitWriter()
+	w.WriteBit(1)
+	w.WriteBits(0x5, 3)
+	w.WriteUnary(2)
+	assert.Equal(t, 7, w.Len())
+	assert.Equal(t, []byte{0xd2}, w.Bytes())
+
+	r := rand.New(rand.NewSource(1))
+	var values []uint64
+	var lengths []uint
+	w = NewBitWriter()
+	for i := 0; i < 1000; i++ {
+		n := uint(r.Intn(65))
+		v := r.Uint64()
+		if n < 64 {
+			v &= 1<<n - 1
+		}
+		values, lengths = append(values, v), append(lengths, n)
+		w.WriteBits(v, n)
+		w.WriteUnary(uint64(n))
+	}
+
+	reader := NewBitReader(w.Bytes())
+	for i, v := range values {
+		got, err := reader.ReadBits(lengths[i])
+		assert.NoError(t, err)
+		assert.Equal(t, v, got)
+		unary, err := reader.ReadUnary()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(lengths[i]), unary)
+	}
+
+	_, err := NewBitReader([]byte{0}).ReadUnary()
+	assert.Equal(t, ErrUnexpectedEnd, err)
+	_, err = NewBitReader([]byte{0}).ReadBits(9)
+	assert.Equal(t, ErrUnexpectedEnd, err)
+}
@@ -0,0 +1,67 @@
+package compression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockCodecs_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var values []uint64
+	for i := 0; i < 1000; i++ {
+		values = append(values, 1)
+	}
+	for i := 0; i < 5000; i++ {
+		switch r.Intn(10) {
+		case 0:
+			// exceptions for PForDelta
+			values = append(values, uint64(r.Int63n(1<<59)))
+		case 1:
+			values = append(values, 0)
+		default:
+			values = append(values, uint64(r.Intn(100)))
+		}
+	}
+
+	for _, codec := range []Codec{PForDelta, Simple8b} {
+		for _, n := range []int{0, 1, 127, 128, 129, len(values)} {
+			data, err := EncodeList(codec, values[:n])
+			assert.NoError(t, err)
+			got, err := DecodeList(codec, data)
+			assert.NoError(t, err)
+			assert.Equal(t, values[:n], got, codec.Name())
+		}
+	}
+
+	data, err := PForDelta.Encode([]uint64{math.MaxUint64, 1, 2})
+	assert.NoError(t, err)
+	got, err := PForDelta.Decode(data, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{math.MaxUint64, 1, 2}, got)
+
+	_, err = Simple8b.Encode([]uint64{1 << 60})
+	assert.Equal(t, ErrInvalidValue, err)
+	_, err = Simple8b.Decode(nil, 1)
+	assert.Equal(t, ErrUnexpectedEnd, err)
+}
+
+func TestBlockCodecs_PostingList(t *testing.T) {
+	l := NewPostingList()
+	assert.NoError(t, l.ReadFromFile("../../lecture-03/data/bowling.txt"))
+
+	for _, codec := range []Codec{PForDelta, Simple8b} {
+		data, err := EncodeDocIDs(codec, l)
+		assert.NoError(t, err)
+		docIDs, err := DecodeDocIDs(codec, data)
+		assert.NoError(t, err)
+		assert.Equal(t, l.DocIDs(), docIDs)
+
+		bits, err := BitsPerPosting(codec, l)
+		assert.NoError(t, err)
+		assert.True(t, bits < 16, codec.Name())
+	}
+}
@@ -0,0 +1,166 @@
+package compression
+
+import (
+	"sort"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+const DefaultBlockSize = 128
+
+// BlockVBPostingList stores the postings in blocks of blockSize, each block
+// holds the variable-byte encoded docID gaps followed by the variable-byte
+// encoded scores. The largest docID and the byte offset of every block form a
+// skip table, so SkipTo only decodes the block it lands in.
+//
+// NOTE: the last decoded block is cached, so a list must not be used by
+// several goroutines at the same time.
+type BlockVBPostingList struct {
+	blockSize    int
+	num          int
+	blockMaxIds  []int64
+	blockOffsets []int
+	data         []byte
+
+	cachedBlock int
+	ids         []int64
+	scores      []int64
+}
+
+func NewBlockVBPostingList(l *PostingList, blockSize int) *BlockVBPostingList {
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	m := &BlockVBPostingList{
+		blockSize:   blockSize,
+		num:         l.Size(),
+		cachedBlock: -1,
+	}
+
+	docIDs, scores := l.DocIDs(), l.Scores()
+	prevMax := int64(-1)
+	for start := 0; start < len(docIDs); start += blockSize {
+		end := start + blockSize
+		if end > len(docIDs) {
+			end = len(docIDs)
+		}
+
+		m.blockOffsets = append(m.blockOffsets, len(m.data))
+		m.blockMaxIds = append(m.blockMaxIds, docIDs[end-1])
+		prev := prevMax
+		for _, id := range docIDs[start:end] {
+			m.data = AppendVB(m.data, uint64(id-prev))
+			prev = id
+		}
+		for _, score := range scores[start:end] {
+			m.data = AppendVB(m.data, uint64(score))
+		}
+		prevMax = docIDs[end-1]
+	}
+	return m
+}
+
+func (m *BlockVBPostingList) Size() int {
+	return m.num
+}
+
+// SizeInBytes returns the size of the encoded postings and the skip table.
+func (m *BlockVBPostingList) SizeInBytes() int {
+	return len(m.data) + 8*len(m.blockMaxIds) + 8*len(m.blockOffsets)
+}
+
+func (m *BlockVBPostingList) GetId(idx int) int64 {
+	m.decodeBlock(idx / m.blockSize)
+	return m.ids[idx%m.blockSize]
+}
+
+func (m *BlockVBPostingList) GetScore(idx int) int64 {
+	m.decodeBlock(idx / m.blockSize)
+	return m.scores[idx%m.blockSize]
+}
+
+// SkipTo gallops over the skip table from the block of from to the first block
+// whose largest docID is not smaller than id, and only decodes that block.
+func (m *BlockVBPostingList) SkipTo(id int64, from int) int {
+	if from >= m.num {
+		return m.num
+	}
+
+	b := from / m.blockSize
+	if m.blockMaxIds[b] < id {
+		// galloping, then binary search in the last jump
+		lo, step := b, 1
+		hi := b + step
+		for hi < len(m.blockMaxIds) && m.blockMaxIds[hi] < id {
+			lo = hi
+			step *= 2
+			hi = b + step
+		}
+		if hi > len(m.blockMaxIds) {
+			hi = len(m.blockMaxIds)
+		}
+		b = lo + sort.Search(hi-lo, func(i int) bool {
+			return m.blockMaxIds[lo+i] >= id
+		})
+		if b == len(m.blockMaxIds) {
+			return m.num
+		}
+		from = b * m.blockSize
+	}
+
+	m.decodeBlock(b)
+	start := b * m.blockSize
+	for pos := from - start; pos < len(m.ids); pos++ {
+		if m.ids[pos] >= id {
+			return start + pos
+		}
+	}
+	// only reached if id is larger than every docID of the list
+	return m.num
+}
+
+// Decompress returns the uncompressed list.
+func (m *BlockVBPostingList) Decompress() *PostingList {
+	l := NewPostingList()
+	l.Reserve(m.num)
+	for b := range m.blockMaxIds {
+		m.decodeBlock(b)
+		for i := range m.ids {
+			l.AddPosting(m.ids[i], m.scores[i])
+		}
+	}
+	return l
+}
+
+func (m *BlockVBPostingList) decodeBlock(b int) {
+	if b == m.cachedBlock {
+		return
+	}
+
+	n := m.blockSize
+	if (b+1)*m.blockSize > m.num {
+		n = m.num - b*m.blockSize
+	}
+	if cap(m.ids) < n {
+		m.ids, m.scores = make([]int64, n), make([]int64, n)
+	}
+	m.ids, m.scores = m.ids[:n], m.scores[:n]
+
+	prev := int64(-1)
+	if b > 0 {
+		prev = m.blockMaxIds[b-1]
+	}
+	pos := m.blockOffsets[b]
+	for i := 0; i < n; i++ {
+		gap, size := ReadVB(m.data[pos:])
+		pos += size
+		prev += int64(gap)
+		m.ids[i] = prev
+	}
+	for i := 0; i < n; i++ {
+		score, size := ReadVB(m.data[pos:])
+		pos += size
+		m.scores[i] = int64(score)
+	}
+	m.cachedBlock = b
+}
@@ -0,0 +1,40 @@
+package compression
+
+import (
+	"testing"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/intersection"
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockVBPostingList(t *testing.T) {
+	l := NewPostingList()
+	assert.NoError(t, l.ReadFromFile("../../lecture-03/data/rug.txt"))
+
+	for _, blockSize := range []int{1, 7, 128, 10000} {
+		c := NewBlockVBPostingList(l, blockSize)
+		assert.Equal(t, l.Size(), c.Size())
+		assert.Equal(t, l.String(), c.Decompress().String())
+		if blockSize >= DefaultBlockSize {
+			assert.True(t, c.SizeInBytes() < 4*l.Size())
+		}
+
+		for _, from := range []int{0, 1, 100, l.Size() - 1, l.Size()} {
+			for _, id := range []int64{0, l.GetId(0), l.GetId(l.Size() / 2), l.GetId(l.Size()-1) + 1} {
+				assert.Equal(t, l.SkipTo(id, from), c.SkipTo(id, from))
+			}
+		}
+	}
+}
+
+func TestBlockVBPostingList_Intersect(t *testing.T) {
+	l1, l2 := NewPostingList(), NewPostingList()
+	assert.NoError(t, l1.ReadFromFile("../../lecture-03/data/bowling.txt"))
+	assert.NoError(t, l2.ReadFromFile("../../lecture-03/data/rug.txt"))
+
+	want := intersection.IntersectBasic(l1, l2).String()
+	c1, c2 := NewBlockVBPostingList(l1, 0), NewBlockVBPostingList(l2, 0)
+	assert.Equal(t, want, intersection.IntersectWithSkipTo(c1, c2).String())
+	assert.Equal(t, want, intersection.IntersectWithSkipTo(l1, c2).String())
+}
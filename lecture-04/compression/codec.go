@@ -0,0 +1,72 @@
+package compression
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidValue = errors.New("value out of range for codec")
+
+// Codec compresses sequences of positive integers (≥ 1), such as docID gaps.
+type Codec interface {
+	Name() string
+	// Encode compresses values, any parameters the decoder needs are stored
+	// in front of the data.
+	Encode(values []uint64) ([]byte, error)
+	// Decode decompresses n values from data.
+	Decode(data []byte, n int) ([]uint64, error)
+}
+
+// EncodeList prefixes the encoded values with their number, so the list can be
+// decoded with DecodeList alone.
+func EncodeList(codec Codec, values []uint64) ([]byte, error) {
+	data, err := codec.Encode(values)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, binary.MaxVarintLen64)
+	header = header[:binary.PutUvarint(header, uint64(len(values)))]
+	return append(header, data...), nil
+}
+
+// maxValuesCodec is implemented by the codecs that can take less than one bit
+// per value, maxValues returns how many values size bytes can hold at most.
+type maxValuesCodec interface {
+	maxValues(size int) uint64
+}
+
+// DecodeList decodes a list written by EncodeList. A number of values that
+// data can't hold, at least one bit per value unless the codec says otherwise,
+// is rejected before anything is allocated.
+func DecodeList(codec Codec, data []byte) ([]uint64, error) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 {
+		return nil, ErrUnexpectedEnd
+	}
+	data = data[size:]
+
+	maxValues := 8 * uint64(len(data))
+	if c, ok := codec.(maxValuesCodec); ok {
+		maxValues = c.maxValues(len(data))
+	}
+	if n > maxValues {
+		return nil, ErrUnexpectedEnd
+	}
+	return codec.Decode(data, int(n))
+}
+
+// AllCodecs returns every codec of this package, Golomb, Rice, rANS and
+// Huffman derive their parameters from the values.
+func AllCodecs() []Codec {
+	return []Codec{
+		EliasGamma,
+		EliasDelta,
+		NewGolombCodec(0),
+		NewRiceCodec(0),
+		VariableByte,
+		PForDelta,
+		Simple8b,
+		NewRANSCodec(nil),
+		NewHuffmanCodec(nil),
+	}
+}
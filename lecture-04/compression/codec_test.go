@@ -0,0 +1,51 @@
+package compression
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeList_NumValues(t *testing.T) {
+	ones, zeros, mostlyZeros := make([]uint64, 100000), make([]uint64, 100000), make([]uint64, 100000)
+	for i := range ones {
+		ones[i] = 1
+		if i%100 == 0 {
+			mostlyZeros[i] = uint64(i)
+		}
+	}
+
+	// the densest lists of every codec still decode
+	tests := []struct {
+		givenName   string
+		givenValues []uint64
+		givenZeros  bool
+	}{
+		{"ones", ones, false},
+		{"zeros", zeros, true},
+		{"mostly zeros", mostlyZeros, true},
+	}
+
+	for _, codec := range AllCodecs() {
+		for _, tt := range tests {
+			data, err := EncodeList(codec, tt.givenValues)
+			// the codecs of positive integers reject zeros
+			if tt.givenZeros && err == ErrInvalidValue {
+				continue
+			}
+			assert.NoError(t, err, codec.Name(), tt.givenName)
+			got, err := DecodeList(codec, data)
+			assert.NoError(t, err, codec.Name(), tt.givenName)
+			assert.Equal(t, tt.givenValues, got, codec.Name(), tt.givenName)
+		}
+
+		// a huge number of values in front of a few bytes
+		data, err := EncodeList(codec, ones)
+		assert.NoError(t, err)
+		huge := make([]byte, binary.MaxVarintLen64)
+		huge = huge[:binary.PutUvarint(huge, 1<<62)]
+		_, err = DecodeList(codec, append(huge, data[len(data)-16:]...))
+		assert.Equal(t, ErrUnexpectedEnd, err, codec.Name())
+	}
+}
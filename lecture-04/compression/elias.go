@@ -0,0 +1,87 @@
+package compression
+
+import "math/bits"
+
+// WriteGamma writes x ≥ 1 as ⌊log2 x⌋ zeros followed by the binary
+// representation of x.
+func WriteGamma(w *BitWriter, x uint64) {
+	n := uint(bits.Len64(x)) - 1
+	w.WriteBits(0, n)
+	w.WriteBits(x, n+1)
+}
+
+func ReadGamma(r *BitReader) (uint64, error) {
+	n, err := r.ReadUnary()
+	if err != nil {
+		return 0, err
+	}
+	if n > 63 {
+		return 0, ErrInvalidValue
+	}
+	low, err := r.ReadBits(uint(n))
+	if err != nil {
+		return 0, err
+	}
+	return 1<<n | low, nil
+}
+
+// WriteDelta writes x ≥ 1 as the gamma code of its length ⌊log2 x⌋ + 1
+// followed by x without its leading one.
+func WriteDelta(w *BitWriter, x uint64) {
+	n := uint(bits.Len64(x))
+	WriteGamma(w, uint64(n))
+	w.WriteBits(x, n-1)
+}
+
+func ReadDelta(r *BitReader) (uint64, error) {
+	n, err := ReadGamma(r)
+	if err != nil {
+		return 0, err
+	}
+	if n > 64 {
+		return 0, ErrInvalidValue
+	}
+	low, err := r.ReadBits(uint(n - 1))
+	if err != nil {
+		return 0, err
+	}
+	return 1<<(n-1) | low, nil
+}
+
+type bitCodec struct {
+	name  string
+	write func(w *BitWriter, x uint64)
+	read  func(r *BitReader) (uint64, error)
+}
+
+func (c bitCodec) Name() string {
+	return c.name
+}
+
+func (c bitCodec) Encode(values []uint64) ([]byte, error) {
+	w := NewBitWriter()
+	for _, v := range values {
+		if v == 0 {
+			return nil, ErrInvalidValue
+		}
+		c.write(w, v)
+	}
+	return w.Bytes(), nil
+}
+
+func (c bitCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	r := NewBitReader(data)
+	values = make([]uint64, n)
+	for i := range values {
+		if values[i], err = c.read(r); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// EliasGamma takes 2⌊log2 x⌋ + 1 bits for x.
+var EliasGamma Codec = bitCodec{name: "elias-gamma", write: WriteGamma, read: ReadGamma}
+
+// EliasDelta takes ⌊log2 x⌋ + 2⌊log2(⌊log2 x⌋ + 1)⌋ + 1 bits for x.
+var EliasDelta Codec = bitCodec{name: "elias-delta", write: WriteDelta, read: ReadDelta}
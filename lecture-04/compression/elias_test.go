@@ -0,0 +1,59 @@
+package compression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEliasCodes(t *testing.T) {
+	tests := []struct {
+		givenCodec Codec
+		givenValue uint64
+		wantBits   string
+	}{
+		{EliasGamma, 1, "1"},
+		{EliasGamma, 2, "010"},
+		{EliasGamma, 9, "0001001"},
+		{EliasDelta, 1, "1"},
+		{EliasDelta, 2, "0100"},
+		{EliasDelta, 9, "00100001"},
+	}
+
+	for _, tt := range tests {
+		w := NewBitWriter()
+		tt.givenCodec.(bitCodec).write(w, tt.givenValue)
+		assert.Equal(t, len(tt.wantBits), w.Len())
+
+		var got []byte
+		r := NewBitReader(w.Bytes())
+		for i := 0; i < len(tt.wantBits); i++ {
+			bit, _ := r.ReadBit()
+			got = append(got, byte('0'+bit))
+		}
+		assert.Equal(t, tt.wantBits, string(got))
+	}
+}
+
+func TestEliasCodes_RoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	values := []uint64{1, 2, 3, math.MaxUint64, math.MaxUint32}
+	for i := 0; i < 1000; i++ {
+		values = append(values, 1+uint64(r.Int63n(1<<uint(r.Intn(62)+1))))
+	}
+
+	for _, codec := range []Codec{EliasGamma, EliasDelta} {
+		data, err := EncodeList(codec, values)
+		assert.NoError(t, err)
+		got, err := DecodeList(codec, data)
+		assert.NoError(t, err)
+		assert.Equal(t, values, got, codec.Name())
+
+		_, err = codec.Encode([]uint64{0})
+		assert.Equal(t, ErrInvalidValue, err)
+		_, err = codec.Decode(data[:len(data)/2], len(values))
+		assert.Equal(t, ErrUnexpectedEnd, err)
+	}
+}
@@ -0,0 +1,49 @@
+package compression
+
+import (
+	"math"
+	"sort"
+)
+
+type SymbolCount struct {
+	Symbol uint64
+	Count  int
+}
+
+// Distribution counts how often each value occurs, the result is ordered by
+// decreasing count and increasing value on ties.
+func Distribution(values []uint64) (ret []SymbolCount) {
+	counts := make(map[uint64]int)
+	for _, v := range values {
+		counts[v]++
+	}
+	for symbol, count := range counts {
+		ret = append(ret, SymbolCount{symbol, count})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Count != ret[j].Count {
+			return ret[i].Count > ret[j].Count
+		}
+		return ret[i].Symbol < ret[j].Symbol
+	})
+	return
+}
+
+// Entropy returns the empirical entropy H = -Σ p·log2(p) of the distribution
+// in bits per symbol. By Shannon's source coding theorem no prefix-free code
+// that encodes the symbols independently can use fewer bits on average. Note
+// that the bound ignores the cost of describing the distribution itself, which
+// matters for short lists with many distinct values.
+func Entropy(distribution []SymbolCount) (h float64) {
+	var total int
+	for _, sc := range distribution {
+		total += sc.Count
+	}
+	for _, sc := range distribution {
+		if sc.Count > 0 {
+			p := float64(sc.Count) / float64(total)
+			h -= p * math.Log2(p)
+		}
+	}
+	return
+}
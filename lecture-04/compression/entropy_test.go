@@ -0,0 +1,17 @@
+package compression
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntropy(t *testing.T) {
+	distribution := Distribution([]uint64{1, 2, 1, 3, 1, 2, 4, 1})
+	assert.Equal(t, []SymbolCount{{1, 4}, {2, 2}, {3, 1}, {4, 1}}, distribution)
+	assert.InDelta(t, 1.75, Entropy(distribution), 1e-9)
+
+	assert.Equal(t, 0.0, Entropy(Distribution([]uint64{7, 7, 7})))
+	assert.InDelta(t, math.Log2(10), Entropy(Distribution([]uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})), 1e-9)
+}
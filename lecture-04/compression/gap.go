@@ -0,0 +1,52 @@
+package compression
+
+import (
+	"errors"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+var ErrUnsorted = errors.New("docIDs must be non-negative and strictly increasing")
+
+// GapEncode returns the differences between consecutive docIDs. The first
+// docID is taken relative to -1, so all gaps are ≥ 1 even for docID 0.
+func GapEncode(docIDs []int64) ([]uint64, error) {
+	gaps := make([]uint64, len(docIDs))
+	prev := int64(-1)
+	for i, id := range docIDs {
+		if id <= prev {
+			return nil, ErrUnsorted
+		}
+		gaps[i] = uint64(id - prev)
+		prev = id
+	}
+	return gaps, nil
+}
+
+func GapDecode(gaps []uint64) []int64 {
+	docIDs := make([]int64, len(gaps))
+	prev := int64(-1)
+	for i, gap := range gaps {
+		prev += int64(gap)
+		docIDs[i] = prev
+	}
+	return docIDs
+}
+
+// EncodeDocIDs gap encodes the docIDs of l and compresses the gaps with codec.
+func EncodeDocIDs(codec Codec, l *PostingList) ([]byte, error) {
+	gaps, err := GapEncode(l.DocIDs())
+	if err != nil {
+		return nil, err
+	}
+	return EncodeList(codec, gaps)
+}
+
+// DecodeDocIDs is the inverse of EncodeDocIDs.
+func DecodeDocIDs(codec Codec, data []byte) ([]int64, error) {
+	gaps, err := DecodeList(codec, data)
+	if err != nil {
+		return nil, err
+	}
+	return GapDecode(gaps), nil
+}
@@ -0,0 +1,33 @@
+package compression
+
+import (
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGapEncode(t *testing.T) {
+	gaps, err := GapEncode([]int64{0, 2, 3, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 1, 3}, gaps)
+	assert.Equal(t, []int64{0, 2, 3, 6}, GapDecode(gaps))
+
+	_, err = GapEncode([]int64{2, 2})
+	assert.Equal(t, ErrUnsorted, err)
+}
+
+func TestEncodeDocIDs(t *testing.T) {
+	for _, filename := range []string{"example1.txt", "bowling.txt", "rug.txt"} {
+		l := NewPostingList()
+		assert.NoError(t, l.ReadFromFile("../../lecture-03/data/"+filename))
+
+		for _, codec := range []Codec{EliasGamma, EliasDelta} {
+			data, err := EncodeDocIDs(codec, l)
+			assert.NoError(t, err)
+			docIDs, err := DecodeDocIDs(codec, data)
+			assert.NoError(t, err)
+			assert.Equal(t, l.DocIDs(), docIDs)
+		}
+	}
+}
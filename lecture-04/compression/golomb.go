@@ -0,0 +1,142 @@
+package compression
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// GolombParameter returns the Golomb parameter M = ⌈ln2 · N/n⌉ which is
+// optimal, as shown in the lecture, when each of N docIDs is in a list of n
+// postings independently with probability p = n/N.
+func GolombParameter(n, N uint64) uint64 {
+	if n == 0 || N <= n {
+		return 1
+	}
+	return uint64(math.Ceil(math.Ln2 * float64(N) / float64(n)))
+}
+
+// RiceParameter rounds the Golomb parameter down to a power of two, so the
+// remainder is always written with the same number of bits.
+func RiceParameter(n, N uint64) uint64 {
+	return 1 << uint(bits.Len64(GolombParameter(n, N))-1)
+}
+
+// GolombCodec writes x ≥ 1 as the unary code of q = (x-1) / M followed by the
+// truncated binary code of r = (x-1) mod M. M is stored in the header of the
+// encoded data; when M is 0 it is derived from the values by
+// GolombParameter (or RiceParameter) with N being the sum of the values, i.e.
+// the largest docID + 1 for gaps.
+type GolombCodec struct {
+	M    uint64
+	Rice bool
+}
+
+func NewGolombCodec(m uint64) GolombCodec {
+	return GolombCodec{M: m}
+}
+
+func NewRiceCodec(m uint64) GolombCodec {
+	return GolombCodec{M: m, Rice: true}
+}
+
+func (c GolombCodec) Name() string {
+	if c.Rice {
+		return "rice"
+	}
+	return "golomb"
+}
+
+func (c GolombCodec) parameter(values []uint64) uint64 {
+	if c.M != 0 {
+		return c.M
+	}
+	var N uint64
+	for _, v := range values {
+		N += v
+	}
+	if c.Rice {
+		return RiceParameter(uint64(len(values)), N)
+	}
+	return GolombParameter(uint64(len(values)), N)
+}
+
+func (c GolombCodec) Encode(values []uint64) ([]byte, error) {
+	m := c.parameter(values)
+	if c.Rice && m&(m-1) != 0 {
+		return nil, ErrInvalidValue
+	}
+	header := make([]byte, binary.MaxVarintLen64)
+	header = header[:binary.PutUvarint(header, m)]
+
+	w := NewBitWriter()
+	for _, v := range values {
+		if v == 0 {
+			return nil, ErrInvalidValue
+		}
+		WriteGolomb(w, v, m)
+	}
+	return append(header, w.Bytes()...), nil
+}
+
+func (c GolombCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	m, size := binary.Uvarint(data)
+	if size <= 0 {
+		return nil, ErrUnexpectedEnd
+	}
+	if m == 0 {
+		return nil, ErrInvalidValue
+	}
+
+	r := NewBitReader(data[size:])
+	values = make([]uint64, n)
+	for i := range values {
+		if values[i], err = ReadGolomb(r, m); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// truncatedBinary returns the number of bits b = ⌈log2 m⌉ and the number of
+// remainders that only take b-1 bits.
+func truncatedBinary(m uint64) (b uint, cutoff uint64) {
+	b = uint(bits.Len64(m - 1))
+	cutoff = 1<<b - m
+	return
+}
+
+func WriteGolomb(w *BitWriter, x, m uint64) {
+	q, r := (x-1)/m, (x-1)%m
+	w.WriteUnary(q)
+
+	b, cutoff := truncatedBinary(m)
+	if r < cutoff {
+		w.WriteBits(r, b-1)
+	} else {
+		w.WriteBits(r+cutoff, b)
+	}
+}
+
+func ReadGolomb(r *BitReader, m uint64) (uint64, error) {
+	q, err := r.ReadUnary()
+	if err != nil {
+		return 0, err
+	}
+
+	b, cutoff := truncatedBinary(m)
+	var rem uint64
+	if b > 0 {
+		if rem, err = r.ReadBits(b - 1); err != nil {
+			return 0, err
+		}
+		if rem >= cutoff {
+			bit, err := r.ReadBit()
+			if err != nil {
+				return 0, err
+			}
+			rem = (rem<<1 | uint64(bit)) - cutoff
+		}
+	}
+	return q*m + rem + 1, nil
+}
@@ -0,0 +1,63 @@
+package compression
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGolombParameter(t *testing.T) {
+	assert.Equal(t, uint64(7), GolombParameter(100, 1000))
+	assert.Equal(t, uint64(4), RiceParameter(100, 1000))
+	assert.Equal(t, uint64(1), GolombParameter(1000, 1000))
+	assert.Equal(t, uint64(1), RiceParameter(0, 1000))
+}
+
+func TestGolombCodec(t *testing.T) {
+	// M = 5: b = 3 and the remainders 0, 1, 2 take 2 bits
+	tests := []struct {
+		givenValue uint64
+		wantBits   int
+	}{
+		{1, 3}, {3, 3}, {4, 4}, {5, 4}, {6, 4}, {11, 5},
+	}
+	for _, tt := range tests {
+		w := NewBitWriter()
+		WriteGolomb(w, tt.givenValue, 5)
+		assert.Equal(t, tt.wantBits, w.Len())
+		got, err := ReadGolomb(NewBitReader(w.Bytes()), 5)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.givenValue, got)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	var values []uint64
+	for i := 0; i < 1000; i++ {
+		values = append(values, 1+uint64(r.Intn(100)))
+	}
+	for _, codec := range []Codec{NewGolombCodec(0), NewGolombCodec(1), NewGolombCodec(13), NewRiceCodec(0), NewRiceCodec(16)} {
+		data, err := EncodeList(codec, values)
+		assert.NoError(t, err)
+		got, err := DecodeList(codec, data)
+		assert.NoError(t, err)
+		assert.Equal(t, values, got)
+	}
+
+	_, err := NewRiceCodec(3).Encode(values)
+	assert.Equal(t, ErrInvalidValue, err)
+}
+
+func TestVariableByte(t *testing.T) {
+	assert.Equal(t, []byte{0x85}, AppendVB(nil, 5))
+	assert.Equal(t, []byte{0x00, 0x81}, AppendVB(nil, 128))
+
+	l := NewPostingList()
+	assert.NoError(t, l.ReadFromFile("../../lecture-03/data/rug.txt"))
+	data, err := EncodeDocIDs(VariableByte, l)
+	assert.NoError(t, err)
+	docIDs, err := DecodeDocIDs(VariableByte, data)
+	assert.NoError(t, err)
+	assert.Equal(t, l.DocIDs(), docIDs)
+}
@@ -0,0 +1,313 @@
+package compression
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+const maxHuffmanCodeLength = 64
+
+var (
+	ErrCodeTooLong     = errors.New("huffman code longer than 64 bits")
+	ErrUnknownSymbol   = errors.New("symbol not in huffman codebook")
+	ErrInvalidCodeword = errors.New("invalid huffman codeword")
+)
+
+// HuffmanCode is a canonical Huffman code: only the code length of every
+// symbol is needed to rebuild it, codes of the same length are consecutive
+// numbers in symbol order.
+type HuffmanCode struct {
+	// symbols ordered by (length, symbol), lengths accordingly
+	symbols []uint64
+	lengths []uint8
+
+	codes map[uint64]codeword
+
+	// for decoding: per length, the first code and the index of its symbol
+	firstCode   [maxHuffmanCodeLength + 1]uint64
+	firstSymbol [maxHuffmanCodeLength + 1]int
+	numCodes    [maxHuffmanCodeLength + 1]int
+}
+
+type codeword struct {
+	code   uint64
+	length uint8
+}
+
+type huffmanNode struct {
+	count  uint64
+	symbol uint64
+	// leaves have no children, children are indexes into the node slice
+	left, right int
+}
+
+type huffmanQueue struct {
+	nodes []huffmanNode
+	items []int
+}
+
+func (q huffmanQueue) Len() int { return len(q.items) }
+func (q huffmanQueue) Less(i, j int) bool {
+	a, b := q.nodes[q.items[i]], q.nodes[q.items[j]]
+	if a.count != b.count {
+		return a.count < b.count
+	}
+	// deterministic trees for equal counts
+	return q.items[i] < q.items[j]
+}
+func (q huffmanQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *huffmanQueue) Push(x interface{}) {
+	q.items = append(q.items, x.(int))
+}
+
+func (q *huffmanQueue) Pop() interface{} {
+	old := q.items
+	n := len(old)
+	x := old[n-1]
+	q.items = old[0 : n-1]
+	return x
+}
+
+// NewHuffmanCode builds the optimal prefix code for the given symbol counts,
+// e.g. word frequencies. Symbols with a count of 0 get no code.
+func NewHuffmanCode(counts map[uint64]uint64) (*HuffmanCode, error) {
+	q := &huffmanQueue{}
+	var symbols []uint64
+	for symbol, count := range counts {
+		if count > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+	for _, symbol := range symbols {
+		q.nodes = append(q.nodes, huffmanNode{count: counts[symbol], symbol: symbol, left: -1, right: -1})
+		q.items = append(q.items, len(q.nodes)-1)
+	}
+	heap.Init(q)
+
+	for q.Len() > 1 {
+		a, b := heap.Pop(q).(int), heap.Pop(q).(int)
+		q.nodes = append(q.nodes, huffmanNode{count: q.nodes[a].count + q.nodes[b].count, left: a, right: b})
+		heap.Push(q, len(q.nodes)-1)
+	}
+
+	lengths := make(map[uint64]uint8)
+	if q.Len() == 1 {
+		var walk func(node, depth int) error
+		walk = func(node, depth int) error {
+			n := q.nodes[node]
+			if n.left < 0 {
+				if depth > maxHuffmanCodeLength {
+					return ErrCodeTooLong
+				}
+				// a single symbol still needs one bit
+				if depth == 0 {
+					depth = 1
+				}
+				lengths[n.symbol] = uint8(depth)
+				return nil
+			}
+			if err := walk(n.left, depth+1); err != nil {
+				return err
+			}
+			return walk(n.right, depth+1)
+		}
+		if err := walk(q.items[0], 0); err != nil {
+			return nil, err
+		}
+	}
+	return NewHuffmanCodeFromLengths(lengths)
+}
+
+// NewHuffmanCodeFromLengths rebuilds the canonical code from code lengths.
+func NewHuffmanCodeFromLengths(lengths map[uint64]uint8) (*HuffmanCode, error) {
+	c := &HuffmanCode{codes: make(map[uint64]codeword, len(lengths))}
+	for symbol, length := range lengths {
+		if length == 0 || length > maxHuffmanCodeLength {
+			return nil, ErrCodeTooLong
+		}
+		c.symbols = append(c.symbols, symbol)
+	}
+	sort.Slice(c.symbols, func(i, j int) bool {
+		li, lj := lengths[c.symbols[i]], lengths[c.symbols[j]]
+		if li != lj {
+			return li < lj
+		}
+		return c.symbols[i] < c.symbols[j]
+	})
+
+	var code uint64
+	var prevLength uint8
+	for i, symbol := range c.symbols {
+		length := lengths[symbol]
+		c.lengths = append(c.lengths, length)
+		if i > 0 {
+			code++
+		}
+		code <<= length - prevLength
+		if length < 64 && code>>length != 0 {
+			// the lengths violate the Kraft inequality
+			return nil, ErrInvalidCodeword
+		}
+		if c.numCodes[length] == 0 {
+			c.firstCode[length] = code
+			c.firstSymbol[length] = i
+		}
+		c.numCodes[length]++
+		c.codes[symbol] = codeword{code: code, length: length}
+		prevLength = length
+	}
+	return c, nil
+}
+
+// Lengths returns the code length of every symbol.
+func (c *HuffmanCode) Lengths() map[uint64]uint8 {
+	lengths := make(map[uint64]uint8, len(c.symbols))
+	for i, symbol := range c.symbols {
+		lengths[symbol] = c.lengths[i]
+	}
+	return lengths
+}
+
+// Codeword returns the code of symbol as a string of 0s and 1s.
+func (c *HuffmanCode) Codeword(symbol uint64) (string, bool) {
+	cw, ok := c.codes[symbol]
+	if !ok {
+		return "", false
+	}
+	b := make([]byte, cw.length)
+	for i := range b {
+		b[i] = '0' + byte(cw.code>>(uint(cw.length)-1-uint(i))&1)
+	}
+	return string(b), true
+}
+
+// AverageCodeLength returns the average number of bits per symbol for the given
+// counts, to compare with their Entropy.
+func (c *HuffmanCode) AverageCodeLength(counts map[uint64]uint64) float64 {
+	var bits, total float64
+	for symbol, count := range counts {
+		bits += float64(count) * float64(c.codes[symbol].length)
+		total += float64(count)
+	}
+	if total == 0 {
+		return 0
+	}
+	return bits / total
+}
+
+func (c *HuffmanCode) Write(w *BitWriter, symbol uint64) error {
+	cw, ok := c.codes[symbol]
+	if !ok {
+		return ErrUnknownSymbol
+	}
+	w.WriteBits(cw.code, uint(cw.length))
+	return nil
+}
+
+func (c *HuffmanCode) Read(r *BitReader) (uint64, error) {
+	var code uint64
+	for length := 1; length <= maxHuffmanCodeLength; length++ {
+		bit, err := r.ReadBit()
+		if err != nil {
+			return 0, err
+		}
+		code = code<<1 | uint64(bit)
+		if c.numCodes[length] > 0 && code >= c.firstCode[length] && code-c.firstCode[length] < uint64(c.numCodes[length]) {
+			return c.symbols[c.firstSymbol[length]+int(code-c.firstCode[length])], nil
+		}
+	}
+	return 0, ErrInvalidCodeword
+}
+
+// MarshalBinary serializes the codebook as the number of symbols followed by
+// (symbol, code length) pairs in canonical order.
+func (c *HuffmanCode) MarshalBinary() ([]byte, error) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf := append([]byte{}, tmp[:binary.PutUvarint(tmp[:], uint64(len(c.symbols)))]...)
+	for i, symbol := range c.symbols {
+		buf = append(buf, tmp[:binary.PutUvarint(tmp[:], symbol)]...)
+		buf = append(buf, c.lengths[i])
+	}
+	return buf, nil
+}
+
+// UnmarshalHuffmanCode reads a codebook written by MarshalBinary and returns
+// the number of bytes read.
+func UnmarshalHuffmanCode(data []byte) (*HuffmanCode, int, error) {
+	n, pos := binary.Uvarint(data)
+	if pos <= 0 {
+		return nil, 0, ErrUnexpectedEnd
+	}
+	lengths := make(map[uint64]uint8)
+	for i := uint64(0); i < n; i++ {
+		symbol, size := binary.Uvarint(data[pos:])
+		if size <= 0 || pos+size >= len(data) {
+			return nil, 0, ErrUnexpectedEnd
+		}
+		pos += size
+		lengths[symbol] = data[pos]
+		pos++
+	}
+	c, err := NewHuffmanCodeFromLengths(lengths)
+	return c, pos, err
+}
+
+// HuffmanCodec encodes values with Code, or with a code built per list and
+// stored in front of the data when Code is nil.
+type HuffmanCodec struct {
+	Code *HuffmanCode
+}
+
+func NewHuffmanCodec(code *HuffmanCode) HuffmanCodec {
+	return HuffmanCodec{Code: code}
+}
+
+func (c HuffmanCodec) Name() string {
+	return "huffman"
+}
+
+func (c HuffmanCodec) Encode(values []uint64) (buf []byte, err error) {
+	code := c.Code
+	if code == nil {
+		counts := make(map[uint64]uint64)
+		for _, v := range values {
+			counts[v]++
+		}
+		if code, err = NewHuffmanCode(counts); err != nil {
+			return nil, err
+		}
+		buf, _ = code.MarshalBinary()
+	}
+
+	w := NewBitWriter()
+	for _, v := range values {
+		if err = code.Write(w, v); err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, w.Bytes()...), nil
+}
+
+func (c HuffmanCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	code := c.Code
+	if code == nil {
+		var size int
+		if code, size, err = UnmarshalHuffmanCode(data); err != nil {
+			return nil, err
+		}
+		data = data[size:]
+	}
+
+	r := NewBitReader(data)
+	values = make([]uint64, n)
+	for i := range values {
+		if values[i], err = code.Read(r); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
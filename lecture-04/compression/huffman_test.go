@@ -0,0 +1,73 @@
+package compression
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHuffmanCode(t *testing.T) {
+	counts := map[uint64]uint64{'a': 45, 'b': 13, 'c': 12, 'd': 16, 'e': 9, 'f': 5}
+	code, err := NewHuffmanCode(counts)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[uint64]uint8{'a': 1, 'b': 3, 'c': 3, 'd': 3, 'e': 4, 'f': 4}, code.Lengths())
+	for symbol, want := range map[uint64]string{'a': "0", 'b': "100", 'c': "101", 'd': "110", 'e': "1110", 'f': "1111"} {
+		got, ok := code.Codeword(symbol)
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+
+	assert.InDelta(t, 2.24, code.AverageCodeLength(counts), 1e-9)
+	var distribution []SymbolCount
+	for symbol, count := range counts {
+		distribution = append(distribution, SymbolCount{symbol, int(count)})
+	}
+	assert.True(t, Entropy(distribution) <= code.AverageCodeLength(counts))
+	assert.True(t, code.AverageCodeLength(counts) < Entropy(distribution)+1)
+
+	single, err := NewHuffmanCode(map[uint64]uint64{7: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64]uint8{7: 1}, single.Lengths())
+
+	_, err = NewHuffmanCodeFromLengths(map[uint64]uint8{1: 1, 2: 1, 3: 1})
+	assert.Equal(t, ErrInvalidCodeword, err)
+}
+
+func TestHuffmanCodec(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var values []uint64
+	counts := make(map[uint64]uint64)
+	for i := 0; i < 10000; i++ {
+		// Zipf distributed, like word frequencies
+		v := uint64(r.ExpFloat64()*r.ExpFloat64()*10) + 1
+		values = append(values, v)
+		counts[v]++
+	}
+
+	codec := NewHuffmanCodec(nil)
+	data, err := EncodeList(codec, values)
+	assert.NoError(t, err)
+	got, err := DecodeList(codec, data)
+	assert.NoError(t, err)
+	assert.Equal(t, values, got)
+
+	code, err := NewHuffmanCode(counts)
+	assert.NoError(t, err)
+	codebook, err := code.MarshalBinary()
+	assert.NoError(t, err)
+	loaded, size, err := UnmarshalHuffmanCode(codebook)
+	assert.NoError(t, err)
+	assert.Equal(t, len(codebook), size)
+	assert.Equal(t, code.Lengths(), loaded.Lengths())
+
+	data, err = NewHuffmanCodec(loaded).Encode(values)
+	assert.NoError(t, err)
+	got, err = NewHuffmanCodec(code).Decode(data, len(values))
+	assert.NoError(t, err)
+	assert.Equal(t, values, got)
+
+	_, err = NewHuffmanCodec(code).Encode([]uint64{1 << 40})
+	assert.Equal(t, ErrUnknownSymbol, err)
+}
@@ -0,0 +1,205 @@
+package compression
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const PForDeltaBlockSize = 128
+
+// PForDelta splits the values into blocks of PForDeltaBlockSize. Each block
+// stores the lowest b bits of every value packed into b-bit slots, with b
+// chosen so that the block is smallest; the values that do not fit
+// (exceptions) additionally store their position and high bits in
+// variable-byte. Zeros are allowed.
+//
+// Block layout: b (1 byte), #exceptions (uvarint), the packed slots, then per
+// exception the position relative to the previous exception and the high bits.
+var PForDelta Codec = pForDeltaCodec{}
+
+type pForDeltaCodec struct{}
+
+func (pForDeltaCodec) Name() string {
+	return "pfordelta"
+}
+
+func (pForDeltaCodec) Encode(values []uint64) ([]byte, error) {
+	var buf []byte
+	for start := 0; start < len(values); start += PForDeltaBlockSize {
+		end := start + PForDeltaBlockSize
+		if end > len(values) {
+			end = len(values)
+		}
+		buf = appendPForDeltaBlock(buf, values[start:end])
+	}
+	return buf, nil
+}
+
+func vbSize(v uint64) int {
+	if v == 0 {
+		return 1
+	}
+	return (bits.Len64(v) + 6) / 7
+}
+
+// pForDeltaWidth returns the slot width minimizing the size of the block.
+func pForDeltaWidth(block []uint64) uint {
+	var best uint
+	bestSize := -1
+	for b := uint(0); b <= 64; b++ {
+		size := (int(b)*len(block) + 7) / 8
+		for _, v := range block {
+			if b < 64 && v>>b != 0 {
+				// 1 byte position delta is a lower bound, good enough here
+				size += 1 + vbSize(v>>b)
+			}
+		}
+		if bestSize < 0 || size < bestSize {
+			best, bestSize = b, size
+		}
+	}
+	return best
+}
+
+func appendPForDeltaBlock(buf []byte, block []uint64) []byte {
+	b := pForDeltaWidth(block)
+	var exceptions []int
+	for i, v := range block {
+		if b < 64 && v>>b != 0 {
+			exceptions = append(exceptions, i)
+		}
+	}
+
+	buf = append(buf, byte(b))
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(len(exceptions)))]...)
+	buf = packBits(buf, block, b)
+
+	prev := 0
+	for _, i := range exceptions {
+		buf = AppendVB(buf, uint64(i-prev))
+		buf = AppendVB(buf, block[i]>>b)
+		prev = i
+	}
+	return buf
+}
+
+// packBits appends the lowest b bits of every value, least significant bit
+// first.
+func packBits(buf []byte, values []uint64, b uint) []byte {
+	var acc uint64
+	var nBits uint
+	for _, v := range values {
+		// at most 32 bits at a time so acc never overflows
+		for rem := b; rem > 0; {
+			take := rem
+			if take > 32 {
+				take = 32
+			}
+			acc |= (v & (1<<take - 1)) << nBits
+			v >>= take
+			nBits += take
+			rem -= take
+			for ; nBits >= 8; nBits -= 8 {
+				buf = append(buf, byte(acc))
+				acc >>= 8
+			}
+		}
+	}
+	if nBits > 0 {
+		buf = append(buf, byte(acc))
+	}
+	return buf
+}
+
+// maxValues is a full block per 2 bytes, b and #exceptions, a block of zeros
+// packs into b = 0.
+func (pForDeltaCodec) maxValues(size int) uint64 {
+	return uint64(size/2) * PForDeltaBlockSize
+}
+
+func (pForDeltaCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	values = make([]uint64, n)
+	pos := 0
+	for start := 0; start < n; start += PForDeltaBlockSize {
+		end := start + PForDeltaBlockSize
+		if end > n {
+			end = n
+		}
+		if pos, err = decodePForDeltaBlock(data, pos, values[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func decodePForDeltaBlock(data []byte, pos int, block []uint64) (int, error) {
+	if pos >= len(data) {
+		return 0, ErrUnexpectedEnd
+	}
+	b := uint(data[pos])
+	pos++
+	if b > 64 {
+		return 0, ErrInvalidValue
+	}
+	numExceptions, size := binary.Uvarint(data[pos:])
+	if size <= 0 {
+		return 0, ErrUnexpectedEnd
+	}
+	pos += size
+
+	packedSize := (int(b)*len(block) + 7) / 8
+	if pos+packedSize > len(data) {
+		return 0, ErrUnexpectedEnd
+	}
+	unpackBits(data[pos:pos+packedSize], block, b)
+	pos += packedSize
+
+	i := 0
+	for e := uint64(0); e < numExceptions; e++ {
+		delta, size := ReadVB(data[pos:])
+		if size == 0 {
+			return 0, ErrUnexpectedEnd
+		}
+		pos += size
+		high, size := ReadVB(data[pos:])
+		if size == 0 {
+			return 0, ErrUnexpectedEnd
+		}
+		pos += size
+
+		i += int(delta)
+		if i >= len(block) {
+			return 0, ErrInvalidValue
+		}
+		block[i] |= high << b
+	}
+	return pos, nil
+}
+
+// unpackBits is the inverse of packBits, data must hold enough bits.
+func unpackBits(data []byte, values []uint64, b uint) {
+	var acc uint64
+	var nBits uint
+	var pos int
+	read := func(n uint) uint64 {
+		for nBits < n {
+			acc |= uint64(data[pos]) << nBits
+			pos++
+			nBits += 8
+		}
+		v := acc & (1<<n - 1)
+		acc >>= n
+		nBits -= n
+		return v
+	}
+
+	for i := range values {
+		if b <= 32 {
+			values[i] = read(b)
+		} else {
+			low := read(32)
+			values[i] = low | read(b-32)<<32
+		}
+	}
+}
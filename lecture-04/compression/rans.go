@@ -0,0 +1,265 @@
+package compression
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// Values are mapped to a small alphabet before entropy coding: values below
+// ransDirectSymbols are symbols themselves, larger values v use the symbol of
+// their bit length and store the bits of v below the leading one raw.
+const (
+	ransDirectSymbols = 16
+	ransNumSymbols    = ransDirectSymbols + 64 - 4
+	ransScaleBits     = 12
+	ransScale         = 1 << ransScaleBits
+	ransLowerBound    = 1 << 23
+)
+
+var ErrInvalidTable = errors.New("invalid rANS frequency table")
+
+func ransSymbol(v uint64) (symbol int, extraBits uint) {
+	if v < ransDirectSymbols {
+		return int(v), 0
+	}
+	n := uint(bits.Len64(v))
+	return ransDirectSymbols + int(n) - 5, n - 1
+}
+
+// FrequencyTable holds symbol frequencies scaled to sum up to 2^12. Every
+// symbol has a frequency of at least 1, so a table built for a corpus can
+// encode any value, at the cost of a little compression.
+type FrequencyTable struct {
+	freqs [ransNumSymbols]uint32
+	cums  [ransNumSymbols + 1]uint32
+	slots []uint8
+}
+
+// NewFrequencyTable builds a table from the values of one or more lists, e.g.
+// the docID gaps of a single list or of a whole corpus.
+func NewFrequencyTable(lists ...[]uint64) *FrequencyTable {
+	var counts [ransNumSymbols]uint64
+	for _, values := range lists {
+		for _, v := range values {
+			s, _ := ransSymbol(v)
+			counts[s]++
+		}
+	}
+	t := &FrequencyTable{}
+	t.normalize(counts)
+	return t
+}
+
+func (t *FrequencyTable) normalize(counts [ransNumSymbols]uint64) {
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+
+	// every symbol keeps at least 1, the rest is shared proportionally
+	var sum uint32
+	largest := 0
+	for s, c := range counts {
+		t.freqs[s] = 1
+		if total > 0 {
+			t.freqs[s] += uint32(c * (ransScale - ransNumSymbols) / total)
+		}
+		sum += t.freqs[s]
+		if t.freqs[s] > t.freqs[largest] {
+			largest = s
+		}
+	}
+	// rounding down leaves some slots, give them to the most frequent symbol
+	t.freqs[largest] += ransScale - sum
+	t.build()
+}
+
+func (t *FrequencyTable) build() {
+	t.slots = make([]uint8, ransScale)
+	for s, f := range t.freqs {
+		t.cums[s+1] = t.cums[s] + f
+		for slot := t.cums[s]; slot < t.cums[s+1]; slot++ {
+			t.slots[slot] = uint8(s)
+		}
+	}
+}
+
+// MarshalBinary serializes the scaled frequencies as uvarints.
+func (t *FrequencyTable) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, ransNumSymbols)
+	var tmp [binary.MaxVarintLen32]byte
+	for _, f := range t.freqs {
+		buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(f))]...)
+	}
+	return buf, nil
+}
+
+func (t *FrequencyTable) UnmarshalBinary(data []byte) error {
+	_, err := t.unmarshal(data)
+	return err
+}
+
+// unmarshal returns the number of bytes read.
+func (t *FrequencyTable) unmarshal(data []byte) (int, error) {
+	var pos int
+	var sum uint64
+	for s := range t.freqs {
+		f, size := binary.Uvarint(data[pos:])
+		if size <= 0 {
+			return 0, ErrUnexpectedEnd
+		}
+		if f == 0 {
+			return 0, ErrInvalidTable
+		}
+		t.freqs[s] = uint32(f)
+		sum += f
+		pos += size
+	}
+	if sum != ransScale {
+		return 0, ErrInvalidTable
+	}
+	t.build()
+	return pos, nil
+}
+
+// RANSCodec is a static rANS entropy coder. With a nil Table, a table is built
+// from the values of each list and stored in front of the encoded data,
+// otherwise the given (e.g. per corpus) table is used and must be known to the
+// decoder.
+//
+// Layout: [table], size of the rANS stream (uvarint), the rANS stream (initial
+// state as 4 bytes big endian, then the renormalization bytes), the raw extra
+// bits of large values.
+type RANSCodec struct {
+	Table *FrequencyTable
+}
+
+func NewRANSCodec(table *FrequencyTable) RANSCodec {
+	return RANSCodec{Table: table}
+}
+
+func (c RANSCodec) Name() string {
+	return "rans"
+}
+
+func (c RANSCodec) Encode(values []uint64) ([]byte, error) {
+	var buf []byte
+	t := c.Table
+	if t == nil {
+		t = NewFrequencyTable(values)
+		buf, _ = t.MarshalBinary()
+	}
+
+	// rANS works like a stack, so the symbols are encoded backwards
+	extra := NewBitWriter()
+	var emitted []byte
+	x := uint32(ransLowerBound)
+	for i := len(values) - 1; i >= 0; i-- {
+		s, _ := ransSymbol(values[i])
+		f, cum := t.freqs[s], t.cums[s]
+		xMax := ((ransLowerBound >> ransScaleBits) << 8) * f
+		for x >= xMax {
+			emitted = append(emitted, byte(x))
+			x >>= 8
+		}
+		x = (x/f)<<ransScaleBits + x%f + cum
+	}
+	for _, v := range values {
+		if _, n := ransSymbol(v); n > 0 {
+			extra.WriteBits(v, n)
+		}
+	}
+
+	var tmp [binary.MaxVarintLen64]byte
+	buf = append(buf, tmp[:binary.PutUvarint(tmp[:], uint64(4+len(emitted)))]...)
+	var state [4]byte
+	binary.BigEndian.PutUint32(state[:], x)
+	buf = append(buf, state[:]...)
+	for i := len(emitted) - 1; i >= 0; i-- {
+		buf = append(buf, emitted[i])
+	}
+	return append(buf, extra.Bytes()...), nil
+}
+
+// maxValues follows from the frequencies: every symbol keeps at least 1, so
+// none has more than ransScale - (ransNumSymbols - 1) and every value costs at
+// least log2(ransScale / (ransScale - ransNumSymbols + 1)) bits, which is more
+// than (ransNumSymbols - 1) / ransScale.
+func (c RANSCodec) maxValues(size int) uint64 {
+	return 8 * uint64(size) * ransScale / (ransNumSymbols - 1)
+}
+
+func (c RANSCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	d, err := c.NewDecoder(data)
+	if err != nil {
+		return nil, err
+	}
+	values = make([]uint64, n)
+	for i := range values {
+		if values[i], err = d.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// RANSDecoder decodes one value at a time.
+type RANSDecoder struct {
+	table  *FrequencyTable
+	stream []byte
+	pos    int
+	x      uint32
+	extra  *BitReader
+}
+
+func (c RANSCodec) NewDecoder(data []byte) (*RANSDecoder, error) {
+	t := c.Table
+	if t == nil {
+		t = &FrequencyTable{}
+		size, err := t.unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		data = data[size:]
+	}
+
+	streamSize, size := binary.Uvarint(data)
+	if size <= 0 || streamSize < 4 || uint64(len(data)-size) < streamSize {
+		return nil, ErrUnexpectedEnd
+	}
+	data = data[size:]
+	return &RANSDecoder{
+		table:  t,
+		stream: data[:streamSize],
+		pos:    4,
+		x:      binary.BigEndian.Uint32(data),
+		extra:  NewBitReader(data[streamSize:]),
+	}, nil
+}
+
+// Next decodes the next value. Reading more values than were encoded returns
+// garbage or ErrUnexpectedEnd, the decoder does not know the count.
+func (d *RANSDecoder) Next() (uint64, error) {
+	t := d.table
+	slot := d.x & (ransScale - 1)
+	s := t.slots[slot]
+	d.x = t.freqs[s]*(d.x>>ransScaleBits) + slot - t.cums[s]
+	for d.x < ransLowerBound {
+		if d.pos >= len(d.stream) {
+			return 0, ErrUnexpectedEnd
+		}
+		d.x = d.x<<8 | uint32(d.stream[d.pos])
+		d.pos++
+	}
+
+	if int(s) < ransDirectSymbols {
+		return uint64(s), nil
+	}
+	n := uint(int(s) - ransDirectSymbols + 4)
+	low, err := d.extra.ReadBits(n)
+	if err != nil {
+		return 0, err
+	}
+	return 1<<n | low, nil
+}
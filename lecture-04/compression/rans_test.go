@@ -0,0 +1,83 @@
+package compression
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRANSCodec(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// geometric values, plus some large ones to exercise the extra bits
+	var values []uint64
+	for i := 0; i < 100000; i++ {
+		v := uint64(1)
+		for r.Float64() < 0.5 {
+			v++
+		}
+		if r.Intn(1000) == 0 {
+			v = r.Uint64()
+		}
+		values = append(values, v)
+	}
+
+	codec := NewRANSCodec(nil)
+	data, err := EncodeList(codec, values)
+	assert.NoError(t, err)
+	got, err := DecodeList(codec, data)
+	assert.NoError(t, err)
+	assert.Equal(t, values, got)
+
+	// close to the entropy (2 bits) of the geometric distribution, the large
+	// values cost about 64 extra bits each
+	bits := float64(8*len(data)) / float64(len(values))
+	assert.InDelta(t, 2+0.064, bits, 0.05)
+
+	_, err = codec.Decode(data[:10], len(values))
+	assert.Error(t, err)
+}
+
+func TestRANSCodec_CorpusTable(t *testing.T) {
+	var lists [][]uint64
+	for _, name := range []string{"bowling.txt", "rug.txt"} {
+		l := NewPostingList()
+		assert.NoError(t, l.ReadFromFile("../../lecture-03/data/"+name))
+		gaps, err := GapEncode(l.DocIDs())
+		assert.NoError(t, err)
+		lists = append(lists, gaps)
+	}
+
+	table := NewFrequencyTable(lists...)
+	serialized, err := table.MarshalBinary()
+	assert.NoError(t, err)
+	loaded := &FrequencyTable{}
+	assert.NoError(t, loaded.UnmarshalBinary(serialized))
+	assert.Equal(t, table, loaded)
+
+	codec := NewRANSCodec(loaded)
+	for _, gaps := range lists {
+		data, err := codec.Encode(gaps)
+		assert.NoError(t, err)
+
+		d, err := codec.NewDecoder(data)
+		assert.NoError(t, err)
+		for _, want := range gaps {
+			got, err := d.Next()
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	}
+
+	// a value never seen while building the table can still be encoded
+	data, err := codec.Encode([]uint64{0, math.MaxUint64})
+	assert.NoError(t, err)
+	got, err := codec.Decode(data, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{0, math.MaxUint64}, got)
+
+	serialized[0] = 0
+	assert.Equal(t, ErrInvalidTable, loaded.UnmarshalBinary(serialized))
+}
@@ -0,0 +1,18 @@
+package compression
+
+import (
+	. "github.com/ZhengHe-MD/ir-freiburg.git/lecture-03/postinglist"
+)
+
+// BitsPerPosting returns the average number of bits codec needs for one docID
+// of l, including the list header.
+func BitsPerPosting(codec Codec, l *PostingList) (float64, error) {
+	if l.Size() == 0 {
+		return 0, nil
+	}
+	data, err := EncodeDocIDs(codec, l)
+	if err != nil {
+		return 0, err
+	}
+	return float64(8*len(data)) / float64(l.Size()), nil
+}
@@ -0,0 +1,103 @@
+package compression
+
+import "encoding/binary"
+
+// Simple8b packs as many values as fit into 64-bit words: the top 4 bits of a
+// word select how the other 60 bits are split. Selectors 0 and 1 stand for
+// 240 and 120 ones, which are frequent gaps in dense lists. Values must be
+// smaller than 2^60, zeros are allowed.
+var Simple8b Codec = simple8bCodec{}
+
+type simple8bCodec struct{}
+
+var simple8bSelectors = [16]struct {
+	n    int
+	bits uint
+}{
+	{240, 0}, {120, 0}, {60, 1}, {30, 2}, {20, 3}, {15, 4}, {12, 5}, {10, 6},
+	{8, 7}, {7, 8}, {6, 10}, {5, 12}, {4, 15}, {3, 20}, {2, 30}, {1, 60},
+}
+
+func (simple8bCodec) Name() string {
+	return "simple-8b"
+}
+
+func (simple8bCodec) Encode(values []uint64) ([]byte, error) {
+	var buf []byte
+	var word [8]byte
+	for i := 0; i < len(values); {
+		selector := -1
+		for s, sel := range simple8bSelectors {
+			if fitsSimple8b(values[i:], sel.n, sel.bits) {
+				selector = s
+				break
+			}
+		}
+		if selector < 0 {
+			return nil, ErrInvalidValue
+		}
+
+		sel := simple8bSelectors[selector]
+		w := uint64(selector) << 60
+		if sel.bits > 0 {
+			for j := 0; j < sel.n && i+j < len(values); j++ {
+				w |= values[i+j] << (uint(j) * sel.bits)
+			}
+		}
+		binary.LittleEndian.PutUint64(word[:], w)
+		buf = append(buf, word[:]...)
+		i += sel.n
+	}
+	return buf, nil
+}
+
+// fitsSimple8b reports if the next (at most) n values fit in the given number
+// of bits, 0 bits meaning all ones. The last word may be partially used.
+func fitsSimple8b(values []uint64, n int, bits uint) bool {
+	if n > len(values) {
+		n = len(values)
+	}
+	for _, v := range values[:n] {
+		if bits == 0 {
+			if v != 1 {
+				return false
+			}
+		} else if v>>bits != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// maxValues is 240 ones per word, see selector 0.
+func (simple8bCodec) maxValues(size int) uint64 {
+	return uint64(size/8) * uint64(simple8bSelectors[0].n)
+}
+
+func (simple8bCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	values = make([]uint64, n)
+	var i int
+	for pos := 0; i < n; pos += 8 {
+		if pos+8 > len(data) {
+			return nil, ErrUnexpectedEnd
+		}
+		w := binary.LittleEndian.Uint64(data[pos:])
+		sel := simple8bSelectors[w>>60]
+		count := sel.n
+		if count > n-i {
+			count = n - i
+		}
+		if sel.bits == 0 {
+			for j := 0; j < count; j++ {
+				values[i+j] = 1
+			}
+		} else {
+			mask := uint64(1)<<sel.bits - 1
+			for j := 0; j < count; j++ {
+				values[i+j] = w >> (uint(j) * sel.bits) & mask
+			}
+		}
+		i += count
+	}
+	return
+}
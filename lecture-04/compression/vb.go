@@ -0,0 +1,57 @@
+package compression
+
+// VariableByte writes 7 bits per byte, least significant group first, and sets
+// the highest bit of the last byte of each value (as in the lecture).
+var VariableByte Codec = variableByteCodec{}
+
+type variableByteCodec struct{}
+
+func (variableByteCodec) Name() string {
+	return "variable-byte"
+}
+
+func (variableByteCodec) Encode(values []uint64) ([]byte, error) {
+	buf := make([]byte, 0, len(values))
+	for _, v := range values {
+		buf = AppendVB(buf, v)
+	}
+	return buf, nil
+}
+
+func (variableByteCodec) Decode(data []byte, n int) (values []uint64, err error) {
+	values = make([]uint64, n)
+	var pos int
+	for i := range values {
+		var size int
+		if values[i], size = ReadVB(data[pos:]); size == 0 {
+			return nil, ErrUnexpectedEnd
+		}
+		pos += size
+	}
+	return
+}
+
+func AppendVB(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v&0x7f))
+		v >>= 7
+	}
+	return append(buf, byte(v)|0x80)
+}
+
+// ReadVB decodes one value and returns it with the number of bytes read, or 0
+// bytes if data ends before the value does.
+func ReadVB(data []byte) (v uint64, size int) {
+	var shift uint
+	for i, b := range data {
+		if shift > 63 {
+			return 0, 0
+		}
+		v |= uint64(b&0x7f) << shift
+		if b&0x80 != 0 {
+			return v, i + 1
+		}
+		shift += 7
+	}
+	return 0, 0
+}
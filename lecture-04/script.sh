@@ -0,0 +1,12 @@
+# bits per posting of the different codes on the lecture-03 posting lists
+go run cmd/bits_per_posting/main.go ../lecture-03/data/bowling.txt ../lecture-03/data/rug.txt
+
+# speed and size of intersections on block variable-byte lists, needs film.txt
+go test -run xxx -bench Intersect ./compression/
+
+# entropy of the gap distributions compared to the bits/gap of every codec
+go run cmd/entropy/main.go ../lecture-03/data/bowling.txt ../lecture-03/data/rug.txt
+
+# canonical huffman code of the words of movies.txt, see lecture-01/script.sh
+go run ../lecture-01/cmd/wordfreq/main.go ../data/movies.txt | sort -k2,2rn > words+frequencies.txt
+go run cmd/huffman/main.go -o words.codebook words+frequencies.txt
@@ -2,58 +2,273 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-05/index"
+	"io"
 	"os"
+	"strings"
 	"time"
 )
 
+type searcher struct {
+	qi *index.QGramIndex
+	// matcher is qi, or a trie of the same entities
+	matcher index.Matcher
+	mwi     *index.MultiWordIndex
+	// workName describes the work counted by the matcher
+	workName string
+	mode     index.MatchMode
+	delta    index.DeltaPolicy
+	k        int
+	topK     bool
+	// phonetic merges the entities that sound like the query, see
+	// QGramIndex.FindMatchesWithPhonetics
+	phonetic bool
+}
+
+type result struct {
+	delta                   int
+	matches                 []index.EntityPEDPair
+	numMatches              int
+	numPEDComputations      int
+	numPEDComputationsSaved int
+	duration                time.Duration
+}
+
+// search returns the ranked matches of x, at most k if topK is set.
+func (s *searcher) search(x string) (r result) {
+	startTime := time.Now()
+	r.delta = s.delta(x)
+
+	if s.mwi != nil {
+		var matches []index.MultiWordMatch
+		matches, r.numPEDComputations = s.mwi.FindMatches(x, s.delta)
+		for _, match := range index.RankMultiWordMatches(matches) {
+			r.matches = append(r.matches, index.EntityPEDPair{Entity: match.Entity, PED: match.PED})
+		}
+	} else if s.topK {
+		r.matches, r.numPEDComputations, r.numPEDComputationsSaved = s.qi.FindTopKMatches(x, r.delta, s.k, s.mode)
+	} else if s.phonetic {
+		var matches []index.EntityPEDPair
+		matches, r.numPEDComputations, _ = s.qi.FindMatchesWithPhonetics(x, r.delta, s.mode)
+		r.matches = index.RankMatches(matches)
+	} else {
+		var matches []index.EntityPEDPair
+		matches, r.numPEDComputations = s.matcher.FindMatchesWithMode(x, r.delta, s.mode)
+		r.matches = index.RankMatches(matches)
+	}
+
+	r.numMatches = len(r.matches)
+	r.duration = time.Now().Sub(startTime)
+	return
+}
+
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Println("Usage: cmd <file>")
+	q := flag.Int("q", 3, "q of the q-grams")
+	deltaPolicy := flag.String("delta", "len/4", `δ of a query, "len/<divisor>" for one error per divisor characters or a fixed "<delta>"`)
+	k := flag.Int("k", 5, "number of matches to show")
+	queries := flag.String("queries", "", "batch mode: file with one query per line, results are written as TSV to stdout")
+	foldAccents := flag.Bool("fold-accents", false, "fold accented letters, e.g. é to e and ü to ue")
+	modeName := flag.String("mode", index.PrefixMatch.String(), "distance to match with: prefix, levenshtein or damerau")
+	multiWord := flag.Bool("multi-word", false, "match every keyword against the words of the entity names")
+	topK := flag.Bool("top-k", false, "only compute the distances needed for the top k matches")
+	indexName := flag.String("index", "qgram", "candidate generator: qgram, or trie for a Levenshtein automaton over a trie of the names")
+	phoneticName := flag.String("phonetic", "", "also match the words that sound like the keywords: soundex, double-metaphone or cologne")
+	load := flag.Bool("load", false, "read the index from a file written with -save instead of building it, <file> is then that file. Its q, folding and phonetic encoder are used, -q, -fold-accents and -phonetic are rejected")
+	save := flag.String("save", "", "write the index to this file after building it")
+	flag.Parse()
+	if flag.NArg() != 1 || *q < 1 || *k < 1 {
+		fmt.Println("Usage: cmd [-q 3] [-delta len/4] [-k 5] [-queries <file>] [-fold-accents] [-mode prefix|levenshtein|damerau] [-multi-word] [-top-k] [-index qgram|trie] [-phonetic soundex|double-metaphone|cologne] [-load] [-save <index file>] <file>")
+		os.Exit(-1)
+	}
+	if *indexName != "qgram" && *indexName != "trie" {
+		fmt.Printf("unknown index %s\n", *indexName)
+		os.Exit(-1)
+	}
+	if *indexName == "trie" && (*multiWord || *topK || *phoneticName != "") {
+		fmt.Println("-multi-word, -top-k and -phonetic need the qgram index")
+		os.Exit(-1)
+	}
+	if *phoneticName != "" && (*multiWord || *topK) {
+		fmt.Println("-phonetic can't be combined with -multi-word or -top-k")
+		os.Exit(-1)
+	}
+	if *load {
+		// q, folding and the phonetic encoder are the ones of the saved index
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "q" || f.Name == "fold-accents" || f.Name == "phonetic" {
+				fmt.Printf("-%s can't be combined with -load, the saved index keeps its own\n", f.Name)
+				os.Exit(-1)
+			}
+		})
+	}
+
+	mode, err := index.ParseMatchMode(*modeName)
+	if err != nil {
+		fmt.Printf("ParseMatchMode err %v", err)
 		os.Exit(-1)
 	}
 
-	qi := index.NewQGramIndex(3)
-	err := qi.BuildFromFile(os.Args[1])
+	delta, err := index.ParseDeltaPolicy(*deltaPolicy)
 	if err != nil {
-		fmt.Printf("BuildFromFile err %v", err)
+		fmt.Printf("ParseDeltaPolicy err %v", err)
 		os.Exit(-1)
 	}
 
+	qi := index.NewQGramIndex(*q)
+	if *load {
+		if err = qi.ReadFromBinaryFile(flag.Arg(0)); err != nil {
+			fmt.Printf("ReadFromBinaryFile err %v", err)
+			os.Exit(-1)
+		}
+		if qi.PhoneticEncoder != nil && (*indexName == "trie" || *multiWord || *topK) {
+			fmt.Println("the saved index is phonetic, which can't be combined with -index trie, -multi-word or -top-k")
+			os.Exit(-1)
+		}
+	} else {
+		qi.FoldAccents = *foldAccents
+		if *phoneticName != "" {
+			qi.PhoneticEncoder, err = index.ParsePhoneticEncoder(*phoneticName)
+			if err != nil {
+				fmt.Printf("ParsePhoneticEncoder err %v", err)
+				os.Exit(-1)
+			}
+		}
+		err = qi.BuildFromFile(flag.Arg(0))
+		if err != nil {
+			fmt.Printf("BuildFromFile err %v", err)
+			os.Exit(-1)
+		}
+	}
+	if *save != "" {
+		if err = qi.WriteToBinaryFile(*save); err != nil {
+			fmt.Printf("WriteToBinaryFile err %v", err)
+			os.Exit(-1)
+		}
+	}
+
+	s := &searcher{qi: qi, matcher: qi, workName: "PEDComputations", mode: mode, delta: delta, k: *k, topK: *topK, phonetic: qi.PhoneticEncoder != nil}
+	if *indexName == "trie" {
+		ti := index.NewTrieIndex()
+		ti.FoldAccents = qi.FoldAccents
+		// the same order gives the same ids
+		for id := 1; id <= len(qi.EntityMap)+len(qi.AliasMap); id++ {
+			if alias, ok := qi.AliasMap[id]; ok {
+				ti.AddAlias(alias.EntityId, alias.Name)
+			} else {
+				ti.AddEntity(qi.EntityMap[id])
+			}
+		}
+		s.matcher = ti
+		s.workName = "visitedTrieNodes"
+	}
+	if *multiWord {
+		s.mwi = index.NewMultiWordIndex(qi)
+		// the last keyword is always matched as a prefix
+		if mode != index.PrefixMatch {
+			s.mwi.WordMode = mode
+		}
+	}
+
+	if *queries != "" {
+		if err = runBatch(s, *queries, os.Stdout); err != nil {
+			fmt.Printf("runBatch err %v", err)
+			os.Exit(-1)
+		}
+		return
+	}
+
 	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Println("Please enter a query:")
 
 	for scanner.Scan() {
-		startTime := time.Now()
-
 		line := scanner.Text()
 		if line == "EOF" {
 			fmt.Println("See you.")
 			break
 		}
 		x := line
-		delta := len(index.Normalize(x)) / 4
-		fmt.Printf("x: %s delta: %d\n", x, delta)
 
-		matches, numPEDComputations := qi.FindMatches(x, delta)
-		sortedMatches := index.RankMatches(matches)
+		r := s.search(x)
+		if s.mwi != nil {
+			fmt.Printf("x: %s\n", x)
+		} else {
+			fmt.Printf("x: %s delta: %d\n", x, r.delta)
+		}
 
-		for i, match := range sortedMatches {
-			if i >= 5 {
+		for i, match := range r.matches {
+			if i >= s.k {
 				break
 			}
-			fmt.Printf("%s\t%s\n", match.Entity.Name, match.Entity.Description)
+			fmt.Printf("%s\t%s\n", displayName(match), match.Entity.Description)
 		}
 
-		if len(sortedMatches) > 5 {
-			fmt.Printf("total #match: %d\n", len(sortedMatches))
+		if r.numMatches > s.k {
+			fmt.Printf("total #match: %d\n", r.numMatches)
 		}
 
-		fmt.Printf("total #PEDComputations: %d\n", numPEDComputations)
-		fmt.Printf("query time: %v\n", time.Now().Sub(startTime))
+		fmt.Printf("total #%s: %d\n", s.workName, r.numPEDComputations)
+		if s.topK {
+			fmt.Printf("total #PEDComputations saved: %d\n", r.numPEDComputationsSaved)
+		}
+		fmt.Printf("query time: %v\n", r.duration)
 		fmt.Println("Please enter a query:")
 	}
 
 	return
 }
+
+// runBatch answers every query of the file, one per line, and writes one TSV
+// line per query with the time in ms, #PED, #RES and the top k names, e.g. to
+// regenerate the result table of the README.
+func runBatch(s *searcher, filename string, w io.Writer) (err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(w)
+	defer func() {
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+
+	if _, err = fmt.Fprintln(bw, "query\tdelta\ttime_ms\tped\tres\ttop"); err != nil {
+		return
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		x := scanner.Text()
+		if strings.TrimSpace(x) == "" {
+			continue
+		}
+
+		r := s.search(x)
+		var names []string
+		for i, match := range r.matches {
+			if i >= s.k {
+				break
+			}
+			names = append(names, displayName(match))
+		}
+		_, err = fmt.Fprintf(bw, "%s\t%d\t%.3f\t%d\t%d\t%s\n",
+			x, r.delta, float64(r.duration.Microseconds())/1000, r.numPEDComputations, r.numMatches, strings.Join(names, "; "))
+		if err != nil {
+			return
+		}
+	}
+	return scanner.Err()
+}
+
+// displayName returns the name of the matched entity, followed by the alias
+// that matched, if any.
+func displayName(match index.EntityPEDPair) string {
+	if match.Alias == "" {
+		return match.Entity.Name
+	}
+	return fmt.Sprintf("%s (%s)", match.Entity.Name, match.Alias)
+}
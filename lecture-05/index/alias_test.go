@@ -0,0 +1,99 @@
+package index
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	newYorkCity = Entity{"New York City", 100, "city in the United States"}
+	newHaven    = Entity{"New Haven", 30, "city in Connecticut"}
+	yorkshire   = Entity{"Yorkshire", 20, "county in England"}
+)
+
+func TestQGramIndex_BuildFromFile_Aliases(t *testing.T) {
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_aliases.tsv"))
+
+	assert.Equal(t, map[int]Entity{1: newYorkCity, 4: newHaven, 5: yorkshire}, q.EntityMap)
+	assert.Equal(t, map[int]Alias{
+		2: {EntityId: 1, Name: "NYC"},
+		3: {EntityId: 1, Name: "Big Apple"},
+		6: {EntityId: 5, Name: "York"},
+	}, q.AliasMap)
+	assert.Equal(t, []string{"NYC", "Big Apple"}, q.Aliases(1))
+	assert.Nil(t, q.Aliases(4))
+	// "$$n" of "New York City", "NYC" and "New Haven"
+	assert.Equal(t, []int{1, 2, 4}, q.InvertedLists["$$n"])
+}
+
+func TestQGramIndex_FindMatches_Aliases(t *testing.T) {
+	tests := []struct {
+		givenX      string
+		givenDelta  int
+		wantMatches []EntityPEDPair
+	}{
+		{
+			"nyc", 0,
+			[]EntityPEDPair{{EntityId: 1, Entity: newYorkCity, PED: 0, Alias: "NYC"}},
+		},
+		{
+			"big aple", 1,
+			[]EntityPEDPair{{EntityId: 1, Entity: newYorkCity, PED: 1, Alias: "Big Apple"}},
+		},
+		// both the name and the alias of Yorkshire match, the name wins
+		{
+			"york", 0,
+			[]EntityPEDPair{{EntityId: 5, Entity: yorkshire, PED: 0}},
+		},
+		{
+			"new", 0,
+			[]EntityPEDPair{
+				{EntityId: 1, Entity: newYorkCity, PED: 0},
+				{EntityId: 4, Entity: newHaven, PED: 0},
+			},
+		},
+	}
+
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_aliases.tsv"))
+	ti := NewTrieIndex()
+	assert.NoError(t, ti.BuildFromFile("example_aliases.tsv"))
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			matches, _ := q.FindMatches(tt.givenX, tt.givenDelta)
+			assert.Equal(t, tt.wantMatches, RankMatches(matches), "qgram")
+
+			matches, _ = ti.FindMatches(tt.givenX, tt.givenDelta)
+			assert.Equal(t, tt.wantMatches, RankMatches(matches), "trie")
+
+			topK, _, _ := q.FindTopKMatches(tt.givenX, tt.givenDelta, 5, PrefixMatch)
+			assert.Equal(t, tt.wantMatches, topK, "top k")
+		})
+	}
+}
+
+func TestQGramIndex_FindMatches_AliasesReportedOncePerName(t *testing.T) {
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_aliases.tsv"))
+
+	matches, numPEDComputations := q.FindMatches("york", 0)
+	assert.Equal(t, []EntityPEDPair{
+		{EntityId: 5, Entity: yorkshire, PED: 0},
+		{EntityId: 5, Entity: yorkshire, PED: 0, Alias: "York"},
+	}, matches)
+	assert.Equal(t, 2, numPEDComputations)
+}
+
+func TestMultiWordIndex_Aliases(t *testing.T) {
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_aliases.tsv"))
+	m := NewMultiWordIndex(q)
+
+	matches, _ := m.FindMatches("nyc cit", FixedDeltaPolicy(0))
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, newYorkCity, matches[0].Entity)
+	}
+}
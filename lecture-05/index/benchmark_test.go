@@ -0,0 +1,56 @@
+package index
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var benchmarkSyllables = []string{
+	"ba", "be", "bi", "bro", "burg", "da", "de", "dor", "ei", "en", "er", "fa",
+	"frei", "ga", "gen", "hau", "he", "ka", "ki", "la", "le", "li", "lo", "ma",
+	"me", "mi", "mo", "na", "ne", "no", "ra", "re", "ri", "ro", "sa", "se", "si",
+	"ski", "sta", "ta", "te", "the", "ti", "to", "tur", "un", "wa", "we", "zi",
+}
+
+// prepareEntities generates n names of 2 to 5 syllables, the same ones in every
+// run, and indexes them with q-grams and in a trie.
+func prepareEntities(n int) (*QGramIndex, *TrieIndex) {
+	r := rand.New(rand.NewSource(2017))
+	qi, ti := NewQGramIndex(3), NewTrieIndex()
+	for i := 0; i < n; i++ {
+		var name strings.Builder
+		for s := 2 + r.Intn(4); s > 0; s-- {
+			name.WriteString(benchmarkSyllables[r.Intn(len(benchmarkSyllables))])
+		}
+		entity := Entity{Name: name.String(), Score: r.Intn(1000)}
+		qi.AddEntity(entity)
+		ti.AddEntity(entity)
+	}
+	return qi, ti
+}
+
+func BenchmarkFindMatches(b *testing.B) {
+	qi, ti := prepareEntities(100000)
+	for _, x := range []string{"the", "breib", "freiburg", "lebauski"} {
+		delta := LengthDeltaPolicy(4)(x)
+		for _, m := range []struct {
+			name    string
+			matcher Matcher
+		}{
+			{"QGram", qi},
+			{"Trie", ti},
+		} {
+			b.Run(m.name+"/"+x, func(b *testing.B) {
+				var numMatches, work int
+				for i := 0; i < b.N; i++ {
+					var matches []EntityPEDPair
+					matches, work = m.matcher.FindMatchesWithMode(x, delta, PrefixMatch)
+					numMatches = len(matches)
+				}
+				b.ReportMetric(float64(numMatches), "matches")
+				b.ReportMetric(float64(work), "candidates")
+			})
+		}
+	}
+}
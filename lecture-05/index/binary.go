@@ -0,0 +1,401 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// The binary format, fixed size integers are little endian, all others are
+// varints as in encoding/binary, a string is its length followed by its bytes:
+//
+//	magic    [4]byte "IRQG"
+//	version  uint32
+//	sections meta, names, lists and, with flagPhonetic, phonetic lists, each
+//	         as length uint64, the data, then checksum uint32, CRC-32 (IEEE)
+//	         of the data
+//
+//	meta     flags uvarint, q uvarint, padding string, phonetic encoder
+//	         string, "" without flagPhonetic
+//	names    count uvarint, then for the ids 1 to count: kind byte, for
+//	         nameEntity name string, score varint, description string, for
+//	         nameAlias entity id uvarint, alias string
+//	lists    count uvarint, then per key in ascending order: key string,
+//	         n uvarint, n x gap uvarint, the first gap is the first id
+//
+// An id occurs once per occurrence of the q-gram, so gaps may be 0.
+const (
+	binaryMagic     = "IRQG"
+	BinaryVersion   = 1
+	binaryHeaderLen = 8
+
+	flagFoldAccents = 1 << 0
+	flagPhonetic    = 1 << 1
+
+	nameEntity = 0
+	nameAlias  = 1
+)
+
+var (
+	ErrInvalidMagic    = errors.New("not a binary q-gram index file")
+	ErrInvalidVersion  = errors.New("unsupported binary q-gram index version")
+	ErrInvalidChecksum = errors.New("binary q-gram index checksum mismatch")
+	ErrTruncated       = errors.New("binary q-gram index file is truncated")
+	ErrCorrupted       = errors.New("binary q-gram index file is corrupted")
+)
+
+// WriteBinary writes the index in the binary format, including the aliases and
+// the phonetic lists.
+func (q *QGramIndex) WriteBinary(w io.Writer) (err error) {
+	bw := bufio.NewWriter(w)
+
+	header := make([]byte, binaryHeaderLen)
+	copy(header, binaryMagic)
+	binary.LittleEndian.PutUint32(header[4:], BinaryVersion)
+	if _, err = bw.Write(header); err != nil {
+		return
+	}
+
+	s := &sectionWriter{w: bw}
+
+	var flags uint64
+	var encoderName string
+	if q.FoldAccents {
+		flags |= flagFoldAccents
+	}
+	if q.PhoneticEncoder != nil {
+		flags |= flagPhonetic
+		encoderName = q.PhoneticEncoder.Name()
+	}
+	s.uvarint(flags)
+	s.uvarint(uint64(q.Q))
+	s.str(q.Padding)
+	s.str(encoderName)
+	if err = s.flush(); err != nil {
+		return
+	}
+
+	numNames := len(q.EntityMap) + len(q.AliasMap)
+	s.uvarint(uint64(numNames))
+	for id := 1; id <= numNames; id++ {
+		if alias, ok := q.AliasMap[id]; ok {
+			s.buf.WriteByte(nameAlias)
+			s.uvarint(uint64(alias.EntityId))
+			s.str(alias.Name)
+			continue
+		}
+		entity := q.EntityMap[id]
+		s.buf.WriteByte(nameEntity)
+		s.str(entity.Name)
+		s.varint(int64(entity.Score))
+		s.str(entity.Description)
+	}
+	if err = s.flush(); err != nil {
+		return
+	}
+
+	if err = s.lists(q.InvertedLists); err != nil {
+		return
+	}
+	if q.PhoneticEncoder != nil {
+		if err = s.lists(q.PhoneticLists); err != nil {
+			return
+		}
+	}
+	return bw.Flush()
+}
+
+func (q *QGramIndex) WriteToBinaryFile(filename string) (err error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return q.WriteBinary(f)
+}
+
+// ReadFromBinaryFile replaces the content of the index by the one of the file,
+// which must have been written by WriteBinary.
+func (q *QGramIndex) ReadFromBinaryFile(filename string) (err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	return q.decodeBinary(data)
+}
+
+func (q *QGramIndex) decodeBinary(data []byte) (err error) {
+	if len(data) < binaryHeaderLen {
+		return ErrTruncated
+	}
+	if string(data[:4]) != binaryMagic {
+		return ErrInvalidMagic
+	}
+	if binary.LittleEndian.Uint32(data[4:]) != BinaryVersion {
+		return ErrInvalidVersion
+	}
+	rest := data[binaryHeaderLen:]
+
+	meta, rest, err := nextSection(rest)
+	if err != nil {
+		return
+	}
+	flags := meta.uvarint()
+	numQ := int(meta.uvarint())
+	padding := meta.str()
+	encoderName := meta.str()
+	if err = meta.done(); err != nil {
+		return
+	}
+	if numQ < 1 {
+		return ErrCorrupted
+	}
+	var encoder PhoneticEncoder
+	if flags&flagPhonetic != 0 {
+		if encoder, err = ParsePhoneticEncoder(encoderName); err != nil {
+			return
+		}
+	}
+
+	names, rest, err := nextSection(rest)
+	if err != nil {
+		return
+	}
+	decoded := NewQGramIndex(numQ)
+	decoded.Padding = padding
+	decoded.FoldAccents = flags&flagFoldAccents != 0
+	decoded.PhoneticEncoder = encoder
+	numNames := int(names.uvarint())
+	for id := 1; id <= numNames && names.err == nil; id++ {
+		switch names.byte() {
+		case nameEntity:
+			entity := Entity{Name: names.str()}
+			entity.Score = int(names.varint())
+			entity.Description = names.str()
+			decoded.EntityMap[id] = entity
+		case nameAlias:
+			entityId := int(names.uvarint())
+			if _, ok := decoded.EntityMap[entityId]; !ok {
+				return ErrCorrupted
+			}
+			decoded.AliasMap[id] = Alias{EntityId: entityId, Name: names.str()}
+			decoded.aliasIds[entityId] = append(decoded.aliasIds[entityId], id)
+		default:
+			return ErrCorrupted
+		}
+	}
+	if err = names.done(); err != nil {
+		return
+	}
+
+	if decoded.InvertedLists, rest, err = decodeLists(rest, numNames); err != nil {
+		return
+	}
+	if encoder != nil {
+		if decoded.PhoneticLists, rest, err = decodeLists(rest, numNames); err != nil {
+			return
+		}
+	}
+	if len(rest) != 0 {
+		return ErrCorrupted
+	}
+
+	*q = *decoded
+	return
+}
+
+// sectionWriter collects a section and writes it with its length and checksum.
+type sectionWriter struct {
+	w       io.Writer
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (s *sectionWriter) uvarint(v uint64) {
+	s.buf.Write(s.scratch[:binary.PutUvarint(s.scratch[:], v)])
+}
+
+func (s *sectionWriter) varint(v int64) {
+	s.buf.Write(s.scratch[:binary.PutVarint(s.scratch[:], v)])
+}
+
+func (s *sectionWriter) str(v string) {
+	s.uvarint(uint64(len(v)))
+	s.buf.WriteString(v)
+}
+
+// lists writes the lists as one section, gap compressed.
+func (s *sectionWriter) lists(lists map[string][]int) error {
+	keys := make([]string, 0, len(lists))
+	for key := range lists {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	s.uvarint(uint64(len(keys)))
+	for _, key := range keys {
+		list := lists[key]
+		s.str(key)
+		s.uvarint(uint64(len(list)))
+		prev := 0
+		for _, id := range list {
+			// NOTE: ids are added in ascending order, see AddEntity
+			if id < prev {
+				return ErrCorrupted
+			}
+			s.uvarint(uint64(id - prev))
+			prev = id
+		}
+	}
+	return s.flush()
+}
+
+func (s *sectionWriter) flush() (err error) {
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(s.buf.Len()))
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(s.buf.Bytes()))
+
+	for _, b := range [][]byte{length[:], s.buf.Bytes(), checksum[:]} {
+		if _, err = s.w.Write(b); err != nil {
+			return
+		}
+	}
+	s.buf.Reset()
+	return
+}
+
+// sectionReader decodes a section, the first error sticks.
+type sectionReader struct {
+	data []byte
+	err  error
+}
+
+// nextSection checks the checksum of the section at the start of data and
+// returns a reader for it and the data after it.
+func nextSection(data []byte) (s *sectionReader, rest []byte, err error) {
+	if len(data) < 8 {
+		return nil, nil, ErrTruncated
+	}
+	length := binary.LittleEndian.Uint64(data)
+	data = data[8:]
+	if uint64(len(data)) < length || uint64(len(data))-length < 4 {
+		return nil, nil, ErrTruncated
+	}
+	body, checksum := data[:length], binary.LittleEndian.Uint32(data[length:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, nil, ErrInvalidChecksum
+	}
+	return &sectionReader{data: body}, data[length+4:], nil
+}
+
+func (s *sectionReader) uvarint() uint64 {
+	if s.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(s.data)
+	if n <= 0 {
+		s.err = ErrCorrupted
+		return 0
+	}
+	s.data = s.data[n:]
+	return v
+}
+
+func (s *sectionReader) varint() int64 {
+	if s.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(s.data)
+	if n <= 0 {
+		s.err = ErrCorrupted
+		return 0
+	}
+	s.data = s.data[n:]
+	return v
+}
+
+func (s *sectionReader) byte() byte {
+	if s.err != nil {
+		return 0
+	}
+	if len(s.data) == 0 {
+		s.err = ErrCorrupted
+		return 0
+	}
+	b := s.data[0]
+	s.data = s.data[1:]
+	return b
+}
+
+func (s *sectionReader) str() string {
+	n := s.uvarint()
+	if s.err != nil {
+		return ""
+	}
+	if uint64(len(s.data)) < n {
+		s.err = ErrCorrupted
+		return ""
+	}
+	v := string(s.data[:n])
+	s.data = s.data[n:]
+	return v
+}
+
+// done returns the first error, or ErrCorrupted if data is left.
+func (s *sectionReader) done() error {
+	if s.err == nil && len(s.data) != 0 {
+		s.err = ErrCorrupted
+	}
+	return s.err
+}
+
+// decodeLists decodes a lists section, all ids must be at most maxId.
+func decodeLists(data []byte, maxId int) (lists map[string][]int, rest []byte, err error) {
+	s, rest, err := nextSection(data)
+	if err != nil {
+		return
+	}
+
+	numKeys := s.uvarint()
+	lists = make(map[string][]int, int(minUint64(numKeys, uint64(len(s.data)))))
+	for i := uint64(0); i < numKeys && s.err == nil; i++ {
+		key := s.str()
+		n := s.uvarint()
+		// every gap takes at least one byte
+		if n > uint64(len(s.data)) {
+			return nil, nil, ErrCorrupted
+		}
+		list := make([]int, n)
+		id := 0
+		for j := range list {
+			id += int(s.uvarint())
+			if id < 1 || id > maxId {
+				return nil, nil, ErrCorrupted
+			}
+			list[j] = id
+		}
+		lists[key] = list
+	}
+	if err = s.done(); err != nil {
+		return nil, nil, err
+	}
+	return
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
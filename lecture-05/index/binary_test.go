@@ -0,0 +1,149 @@
+package index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQGramIndex_Binary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qgram")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		givenFilename    string
+		givenFoldAccents bool
+		givenEncoder     PhoneticEncoder
+		givenQuery       string
+	}{
+		{"example.tsv", false, nil, "frei"},
+		{"example_unicode.tsv", true, nil, "amelie"},
+		{"example_aliases.tsv", false, DoubleMetaphoneEncoder{}, "nyc"},
+		{"example_multi_word.tsv", false, ColognePhoneticsEncoder{}, "lebovski"},
+	}
+
+	for _, tt := range tests {
+		want := NewQGramIndex(3)
+		want.FoldAccents = tt.givenFoldAccents
+		want.PhoneticEncoder = tt.givenEncoder
+		assert.NoError(t, want.BuildFromFile(tt.givenFilename))
+
+		filename := filepath.Join(dir, tt.givenFilename+".bin")
+		assert.NoError(t, want.WriteToBinaryFile(filename))
+
+		got := NewQGramIndex(5)
+		assert.NoError(t, got.ReadFromBinaryFile(filename))
+		assert.Equal(t, want, got, tt.givenFilename)
+
+		wantMatches, _, _ := want.FindMatchesWithPhonetics(tt.givenQuery, 1, PrefixMatch)
+		gotMatches, _, _ := got.FindMatchesWithPhonetics(tt.givenQuery, 1, PrefixMatch)
+		assert.NotEmpty(t, gotMatches, tt.givenFilename)
+		assert.Equal(t, wantMatches, gotMatches, tt.givenFilename)
+	}
+}
+
+func TestQGramIndex_BinaryGaps(t *testing.T) {
+	q := NewQGramIndex(3)
+	q.InvertedLists["foo"] = []int{1, 1, 300, 301}
+	q.EntityMap = map[int]Entity{}
+	for id := 1; id <= 301; id++ {
+		q.EntityMap[id] = Entity{Name: "foo"}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, q.WriteBinary(&buf))
+	got := NewQGramIndex(3)
+	err := got.decodeBinary(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1, 300, 301}, got.InvertedLists["foo"])
+
+	// the gaps 1, 0, 299 and 1 take 5 bytes
+	rest := buf.Bytes()[binaryHeaderLen:]
+	for i := 0; i < 2; i++ {
+		_, rest, err = nextSection(rest)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, uint64(1+1+3+1+5), binary.LittleEndian.Uint64(rest))
+
+	q.InvertedLists["bar"] = []int{2, 1}
+	assert.Equal(t, ErrCorrupted, q.WriteBinary(&bytes.Buffer{}))
+}
+
+// An alias of an earlier entity must keep the phonetic lists ascending.
+func TestQGramIndex_BinaryAliasOfEarlierEntity(t *testing.T) {
+	q := NewQGramIndex(3)
+	q.PhoneticEncoder = SoundexEncoder{}
+	smith := q.AddEntity(Entity{Name: "Smith"})
+	zed := q.AddEntity(Entity{Name: "Zed"})
+	q.AddAlias(smith, "Zedd")
+	q.AddAlias(zed, "Zedd")
+	assert.Equal(t, []int{smith, zed}, q.PhoneticLists["Z300"])
+
+	var buf bytes.Buffer
+	assert.NoError(t, q.WriteBinary(&buf))
+	got := NewQGramIndex(3)
+	assert.NoError(t, got.decodeBinary(buf.Bytes()))
+	assert.Equal(t, q, got)
+}
+
+func TestQGramIndex_BinaryErrors(t *testing.T) {
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_aliases.tsv"))
+	var buf bytes.Buffer
+	assert.NoError(t, q.WriteBinary(&buf))
+	data := buf.Bytes()
+
+	// a byte in every section: the meta section, the names and the lists
+	for _, i := range []int{binaryHeaderLen + 8, len(data) / 3, len(data) - 10} {
+		corrupted := append([]byte{}, data...)
+		corrupted[i] ^= 1
+		assert.Equal(t, ErrInvalidChecksum, NewQGramIndex(3).decodeBinary(corrupted), i)
+	}
+
+	wrongVersion := append([]byte{}, data...)
+	wrongVersion[4] = 2
+	assert.Equal(t, ErrInvalidVersion, NewQGramIndex(3).decodeBinary(wrongVersion))
+
+	assert.Equal(t, ErrInvalidMagic, NewQGramIndex(3).decodeBinary(append([]byte("XXXX"), data[4:]...)))
+	assert.Equal(t, ErrTruncated, NewQGramIndex(3).decodeBinary(data[:4]))
+	assert.Equal(t, ErrTruncated, NewQGramIndex(3).decodeBinary(data[:len(data)-1]))
+	assert.Equal(t, ErrCorrupted, NewQGramIndex(3).decodeBinary(append(append([]byte{}, data...), 0)))
+
+	// a failed read leaves the index untouched
+	assert.Equal(t, q, func() *QGramIndex {
+		got := NewQGramIndex(3)
+		assert.NoError(t, got.decodeBinary(data))
+		assert.Error(t, got.decodeBinary(data[:len(data)-1]))
+		return got
+	}())
+}
+
+func BenchmarkQGramIndex_Binary(b *testing.B) {
+	qi, _ := prepareEntities(100000)
+	var buf bytes.Buffer
+	if err := qi.WriteBinary(&buf); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Build", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			q := NewQGramIndex(3)
+			for id := 1; id <= len(qi.EntityMap); id++ {
+				q.AddEntity(qi.EntityMap[id])
+			}
+		}
+	})
+	b.Run("Load", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := NewQGramIndex(3).decodeBinary(buf.Bytes()); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(buf.Len()), "bytes")
+	})
+}
@@ -0,0 +1,90 @@
+package index
+
+import (
+	"strings"
+)
+
+// ColognePhonetics returns the Kölner Phonetik code of the word, which is
+// tailored to German names, e.g. "Meyer", "Maier" and "Mayr" to "67". Every
+// letter gets a digit depending on its neighbours, then adjacent equal digits
+// are coded once and all 0s but a leading one are dropped. It returns "" for a
+// word without letters.
+func ColognePhonetics(word string) string {
+	letters := phoneticLetters(word)
+
+	at := func(i int) rune {
+		if i < 0 || i >= len(letters) {
+			return 0
+		}
+		return letters[i]
+	}
+
+	var digits []byte
+	for i, r := range letters {
+		prev, next := at(i-1), at(i+1)
+		switch r {
+		case 'A', 'E', 'I', 'J', 'O', 'U', 'Y':
+			digits = append(digits, '0')
+		case 'H':
+			// not coded
+		case 'B':
+			digits = append(digits, '1')
+		case 'P':
+			if next == 'H' {
+				digits = append(digits, '3')
+			} else {
+				digits = append(digits, '1')
+			}
+		case 'D', 'T':
+			if strings.ContainsRune("CSZ", next) && next != 0 {
+				digits = append(digits, '8')
+			} else {
+				digits = append(digits, '2')
+			}
+		case 'F', 'V', 'W':
+			digits = append(digits, '3')
+		case 'G', 'K', 'Q':
+			digits = append(digits, '4')
+		case 'C':
+			if i == 0 {
+				if next != 0 && strings.ContainsRune("AHKLOQRUX", next) {
+					digits = append(digits, '4')
+				} else {
+					digits = append(digits, '8')
+				}
+			} else if prev == 'S' || prev == 'Z' {
+				digits = append(digits, '8')
+			} else if next != 0 && strings.ContainsRune("AHKOQUX", next) {
+				digits = append(digits, '4')
+			} else {
+				digits = append(digits, '8')
+			}
+		case 'X':
+			if prev == 'C' || prev == 'K' || prev == 'Q' {
+				digits = append(digits, '8')
+			} else {
+				digits = append(digits, '4', '8')
+			}
+		case 'L':
+			digits = append(digits, '5')
+		case 'M', 'N':
+			digits = append(digits, '6')
+		case 'R':
+			digits = append(digits, '7')
+		case 'S', 'Z':
+			digits = append(digits, '8')
+		}
+	}
+
+	var code []byte
+	for i, d := range digits {
+		if i > 0 && d == digits[i-1] {
+			continue
+		}
+		if d == '0' && i > 0 {
+			continue
+		}
+		code = append(code, d)
+	}
+	return string(code)
+}
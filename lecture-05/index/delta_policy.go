@@ -0,0 +1,46 @@
+package index
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DeltaPolicy returns the δ allowed for the query x.
+type DeltaPolicy func(x string) int
+
+var ErrInvalidDeltaPolicy = errors.New(`invalid delta policy, want "len/<divisor>" or "<delta>"`)
+
+// LengthDeltaPolicy allows one error per divisor characters of the normalized
+// query, the lecture uses a divisor of 4.
+func LengthDeltaPolicy(divisor int) DeltaPolicy {
+	return func(x string) int {
+		return utf8.RuneCountInString(Normalize(x)) / divisor
+	}
+}
+
+// FixedDeltaPolicy allows delta errors for every query.
+func FixedDeltaPolicy(delta int) DeltaPolicy {
+	return func(x string) int {
+		return delta
+	}
+}
+
+// ParseDeltaPolicy parses "len/<divisor>", e.g. "len/4", as LengthDeltaPolicy
+// and "<delta>", e.g. "2", as FixedDeltaPolicy.
+func ParseDeltaPolicy(s string) (DeltaPolicy, error) {
+	if strings.HasPrefix(s, "len/") {
+		divisor, err := strconv.Atoi(strings.TrimPrefix(s, "len/"))
+		if err != nil || divisor <= 0 {
+			return nil, ErrInvalidDeltaPolicy
+		}
+		return LengthDeltaPolicy(divisor), nil
+	}
+
+	delta, err := strconv.Atoi(s)
+	if err != nil || delta < 0 {
+		return nil, ErrInvalidDeltaPolicy
+	}
+	return FixedDeltaPolicy(delta), nil
+}
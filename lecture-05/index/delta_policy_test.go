@@ -0,0 +1,35 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDeltaPolicy(t *testing.T) {
+	tests := []struct {
+		givenPolicy string
+		givenX      string
+		wantDelta   int
+		wantErr     error
+	}{
+		{"len/4", "freiburg", 2, nil},
+		{"len/4", "Frei, burg !!", 2, nil},
+		{"len/4", "fürs", 1, nil},
+		{"len/3", "freiburg", 2, nil},
+		{"2", "frei", 2, nil},
+		{"0", "freiburg", 0, nil},
+		{"len/0", "", 0, ErrInvalidDeltaPolicy},
+		{"len/x", "", 0, ErrInvalidDeltaPolicy},
+		{"-1", "", 0, ErrInvalidDeltaPolicy},
+		{"half", "", 0, ErrInvalidDeltaPolicy},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseDeltaPolicy(tt.givenPolicy)
+		assert.Equal(t, tt.wantErr, err, tt.givenPolicy)
+		if err == nil {
+			assert.Equal(t, tt.wantDelta, policy(tt.givenX), tt.givenPolicy)
+		}
+	}
+}
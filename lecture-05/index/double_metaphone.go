@@ -0,0 +1,632 @@
+package index
+
+import (
+	"strings"
+)
+
+// DoubleMetaphone returns the primary and the alternate Double Metaphone code
+// of the word, at most four characters each, following the original algorithm
+// of Lawrence Philips. The alternate code covers a second pronunciation, e.g.
+// "Smith" gives "SM0" and "XMT", "Schmidt" gives "XMT" and "SMT". It is equal
+// to the primary code if the word has only one pronunciation.
+func DoubleMetaphone(word string) (primary, alternate string) {
+	var letters []rune
+	for _, r := range word {
+		switch r {
+		case 'ç', 'Ç':
+			letters = append(letters, 'Ç')
+		case 'ñ', 'Ñ':
+			letters = append(letters, 'Ñ')
+		default:
+			letters = append(letters, phoneticLetters(string(r))...)
+		}
+	}
+	if len(letters) == 0 {
+		return
+	}
+
+	m := &metaphone{word: letters, length: len(letters), last: len(letters) - 1}
+	m.encode()
+	return truncate(m.primary.String(), 4), truncate(m.alternate.String(), 4)
+}
+
+type metaphone struct {
+	word         []rune
+	length, last int
+	current      int
+
+	primary, alternate strings.Builder
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// at returns the letter at i, 0 outside of the word.
+func (m *metaphone) at(i int) rune {
+	if i < 0 || i >= m.length {
+		return 0
+	}
+	return m.word[i]
+}
+
+// stringAt checks if the word contains one of the candidates at start, all
+// candidates have the same length.
+func (m *metaphone) stringAt(start int, candidates ...string) bool {
+	if start < 0 {
+		return false
+	}
+	for _, c := range candidates {
+		n := len(c)
+		if start+n > m.length {
+			continue
+		}
+		if string(m.word[start:start+n]) == c {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaphone) isVowel(i int) bool {
+	switch m.at(i) {
+	case 'A', 'E', 'I', 'O', 'U', 'Y':
+		return true
+	}
+	return false
+}
+
+func (m *metaphone) slavoGermanic() bool {
+	s := string(m.word)
+	return strings.ContainsAny(s, "WK") || strings.Contains(s, "CZ")
+}
+
+// add adds main to both codes.
+func (m *metaphone) add(main string) {
+	m.primary.WriteString(main)
+	m.alternate.WriteString(main)
+}
+
+// addAlt adds main to the primary and alt to the alternate code, a " " as alt
+// adds nothing to the alternate code.
+func (m *metaphone) addAlt(main, alt string) {
+	m.primary.WriteString(main)
+	if alt != " " {
+		m.alternate.WriteString(alt)
+	}
+}
+
+// germanic reports the spellings that keep the hard c and g.
+func (m *metaphone) germanic() bool {
+	return m.stringAt(0, "VAN ", "VON ") || m.stringAt(0, "SCH")
+}
+
+func (m *metaphone) encode() {
+	// skip these when at start of word
+	if m.stringAt(0, "GN", "KN", "PN", "WR", "PS") {
+		m.current++
+	}
+	// initial X is pronounced Z, e.g. Xavier
+	if m.at(0) == 'X' {
+		m.add("S")
+		m.current++
+	}
+
+	for m.primary.Len() < 4 || m.alternate.Len() < 4 {
+		if m.current >= m.length {
+			break
+		}
+
+		c := m.at(m.current)
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			// all initial vowels map to A
+			if m.current == 0 {
+				m.add("A")
+			}
+			m.current++
+		case 'B':
+			m.add("P")
+			m.skipDouble('B')
+		case 'Ç':
+			m.add("S")
+			m.current++
+		case 'C':
+			m.encodeC()
+		case 'D':
+			if m.stringAt(m.current, "DG") {
+				if m.stringAt(m.current+2, "I", "E", "Y") {
+					// e.g. edge
+					m.add("J")
+					m.current += 3
+				} else {
+					// e.g. edgar
+					m.add("TK")
+					m.current += 2
+				}
+			} else if m.stringAt(m.current, "DT", "DD") {
+				m.add("T")
+				m.current += 2
+			} else {
+				m.add("T")
+				m.current++
+			}
+		case 'F':
+			m.add("F")
+			m.skipDouble('F')
+		case 'G':
+			m.encodeG()
+		case 'H':
+			// only kept if first or after a vowel, and before a vowel
+			if (m.current == 0 || m.isVowel(m.current-1)) && m.isVowel(m.current+1) {
+				m.add("H")
+				m.current += 2
+			} else {
+				m.current++
+			}
+		case 'J':
+			m.encodeJ()
+		case 'K':
+			m.add("K")
+			m.skipDouble('K')
+		case 'L':
+			if m.at(m.current+1) == 'L' {
+				// spanish, e.g. cabrillo, gallegos
+				if (m.current == m.length-3 && m.stringAt(m.current-1, "ILLO", "ILLA", "ALLE")) ||
+					((m.stringAt(m.last-1, "AS", "OS") || m.stringAt(m.last, "A", "O")) && m.stringAt(m.current-1, "ALLE")) {
+					m.addAlt("L", " ")
+					m.current += 2
+					break
+				}
+				m.current += 2
+			} else {
+				m.current++
+			}
+			m.add("L")
+		case 'M':
+			// e.g. dumb, thumb
+			if (m.stringAt(m.current-1, "UMB") && (m.current+1 == m.last || m.stringAt(m.current+2, "ER"))) ||
+				m.at(m.current+1) == 'M' {
+				m.current += 2
+			} else {
+				m.current++
+			}
+			m.add("M")
+		case 'N':
+			m.add("N")
+			m.skipDouble('N')
+		case 'Ñ':
+			m.add("N")
+			m.current++
+		case 'P':
+			if m.at(m.current+1) == 'H' {
+				m.add("F")
+				m.current += 2
+				break
+			}
+			// also campbell, raspberry
+			if m.stringAt(m.current+1, "P", "B") {
+				m.current += 2
+			} else {
+				m.current++
+			}
+			m.add("P")
+		case 'Q':
+			m.add("K")
+			m.skipDouble('Q')
+		case 'R':
+			// french, e.g. rogier, but not hochmeier
+			if m.current == m.last && !m.slavoGermanic() && m.stringAt(m.current-2, "IE") &&
+				!m.stringAt(m.current-4, "ME", "MA") {
+				m.addAlt("", "R")
+			} else {
+				m.add("R")
+			}
+			m.skipDouble('R')
+		case 'S':
+			m.encodeS()
+		case 'T':
+			m.encodeT()
+		case 'V':
+			m.add("F")
+			m.skipDouble('V')
+		case 'W':
+			m.encodeW()
+		case 'X':
+			// french, e.g. breaux
+			if !(m.current == m.last && (m.stringAt(m.current-3, "IAU", "EAU") || m.stringAt(m.current-2, "AU", "OU"))) {
+				m.add("KS")
+			}
+			if m.stringAt(m.current+1, "C", "X") {
+				m.current += 2
+			} else {
+				m.current++
+			}
+		case 'Z':
+			// chinese pinyin, e.g. zhao
+			if m.at(m.current+1) == 'H' {
+				m.add("J")
+				m.current += 2
+				break
+			}
+			if m.stringAt(m.current+1, "ZO", "ZI", "ZA") ||
+				(m.slavoGermanic() && m.current > 0 && m.at(m.current-1) != 'T') {
+				m.addAlt("S", "TS")
+			} else {
+				m.add("S")
+			}
+			m.skipDouble('Z')
+		default:
+			m.current++
+		}
+	}
+}
+
+// skipDouble advances over the current letter, and the next one if it is r.
+func (m *metaphone) skipDouble(r rune) {
+	if m.at(m.current+1) == r {
+		m.current += 2
+	} else {
+		m.current++
+	}
+}
+
+func (m *metaphone) encodeC() {
+	cur := m.current
+	switch {
+	// various germanic
+	case cur > 1 && !m.isVowel(cur-2) && m.stringAt(cur-1, "ACH") &&
+		m.at(cur+2) != 'I' && (m.at(cur+2) != 'E' || m.stringAt(cur-2, "BACHER", "MACHER")):
+		m.add("K")
+		m.current += 2
+	// caesar
+	case cur == 0 && m.stringAt(cur, "CAESAR"):
+		m.add("S")
+		m.current += 2
+	// italian chianti
+	case m.stringAt(cur, "CHIA"):
+		m.add("K")
+		m.current += 2
+	case m.stringAt(cur, "CH"):
+		m.encodeCH()
+	// e.g. czerny
+	case m.stringAt(cur, "CZ") && !m.stringAt(cur-2, "WICZ"):
+		m.addAlt("S", "X")
+		m.current += 2
+	// e.g. focaccia
+	case m.stringAt(cur+1, "CIA"):
+		m.add("X")
+		m.current += 3
+	// double c, but not McClellan
+	case m.stringAt(cur, "CC") && !(cur == 1 && m.at(0) == 'M'):
+		// bellocchio, but not bacchus
+		if m.stringAt(cur+2, "I", "E", "H") && !m.stringAt(cur+2, "HU") {
+			if (cur == 1 && m.at(cur-1) == 'A') || m.stringAt(cur-1, "UCCEE", "UCCES") {
+				// accident, accede, succeed
+				m.add("KS")
+			} else {
+				// bacci, bertucci
+				m.add("X")
+			}
+			m.current += 3
+		} else {
+			// Pierce's rule
+			m.add("K")
+			m.current += 2
+		}
+	case m.stringAt(cur, "CK", "CG", "CQ"):
+		m.add("K")
+		m.current += 2
+	case m.stringAt(cur, "CI", "CE", "CY"):
+		// italian vs. english
+		if m.stringAt(cur, "CIO", "CIE", "CIA") {
+			m.addAlt("S", "X")
+		} else {
+			m.add("S")
+		}
+		m.current += 2
+	default:
+		m.add("K")
+		if m.stringAt(cur+1, "C", "K", "Q") && !m.stringAt(cur+1, "CE", "CI") {
+			m.current += 2
+		} else {
+			m.current++
+		}
+	}
+}
+
+func (m *metaphone) encodeCH() {
+	cur := m.current
+	defer func() { m.current += 2 }()
+
+	// michael
+	if cur > 0 && m.stringAt(cur, "CHAE") {
+		m.addAlt("K", "X")
+		return
+	}
+	// greek roots, e.g. chemistry, chorus
+	if cur == 0 && (m.stringAt(cur+1, "HARAC", "HARIS") || m.stringAt(cur+1, "HOR", "HYM", "HIA", "HEM")) &&
+		!m.stringAt(0, "CHORE") {
+		m.add("K")
+		return
+	}
+	// germanic, greek, or otherwise ch for kh sound
+	if m.germanic() ||
+		// architect, but not arch, orchestra, orchid
+		m.stringAt(cur-2, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.stringAt(cur+2, "T", "S") ||
+		// e.g. wachtler, wechsler, but not tichner
+		((m.stringAt(cur-1, "A", "O", "U", "E") || cur == 0) &&
+			(cur+2 >= m.length || m.stringAt(cur+2, "L", "R", "N", "M", "B", "H", "F", "V", "W"))) {
+		m.add("K")
+		return
+	}
+	if cur > 0 {
+		if m.stringAt(0, "MC") {
+			// e.g. McHugh
+			m.add("K")
+		} else {
+			m.addAlt("X", "K")
+		}
+	} else {
+		m.add("X")
+	}
+}
+
+func (m *metaphone) encodeG() {
+	cur := m.current
+	next := m.at(cur + 1)
+
+	if next == 'H' {
+		if cur > 0 && !m.isVowel(cur-1) {
+			m.add("K")
+		} else if cur == 0 {
+			// ghislane, ghiradelli
+			if m.at(cur+2) == 'I' {
+				m.add("J")
+			} else {
+				m.add("K")
+			}
+		} else if (cur > 1 && m.stringAt(cur-2, "B", "H", "D")) ||
+			// e.g. bough
+			(cur > 2 && m.stringAt(cur-3, "B", "H", "D")) ||
+			// e.g. broughton
+			(cur > 3 && m.stringAt(cur-4, "B", "H")) {
+			// Parker's rule, e.g. hugh
+		} else if cur > 2 && m.at(cur-1) == 'U' && m.stringAt(cur-3, "C", "G", "L", "R", "T") {
+			// e.g. laugh, McLaughlin, cough, rough
+			m.add("F")
+		} else if m.at(cur-1) != 'I' {
+			m.add("K")
+		}
+		m.current += 2
+		return
+	}
+
+	if next == 'N' {
+		if cur == 1 && m.isVowel(0) && !m.slavoGermanic() {
+			m.addAlt("KN", "N")
+		} else if !m.stringAt(cur+2, "EY") && !m.slavoGermanic() {
+			// not e.g. cagney
+			m.addAlt("N", "KN")
+		} else {
+			m.add("KN")
+		}
+		m.current += 2
+		return
+	}
+
+	// tagliaro
+	if m.stringAt(cur+1, "LI") && !m.slavoGermanic() {
+		m.addAlt("KL", "L")
+		m.current += 2
+		return
+	}
+
+	// -ges-, -gep-, -gel-, -gie- at beginning
+	if cur == 0 && (next == 'Y' ||
+		m.stringAt(cur+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")) {
+		m.addAlt("K", "J")
+		m.current += 2
+		return
+	}
+
+	// -ger-, -gy-
+	if (m.stringAt(cur+1, "ER") || next == 'Y') && !m.stringAt(0, "DANGER", "RANGER", "MANGER") &&
+		!m.stringAt(cur-1, "E", "I") && !m.stringAt(cur-1, "RGY", "OGY") {
+		m.addAlt("K", "J")
+		m.current += 2
+		return
+	}
+
+	// italian, e.g. biaggi
+	if m.stringAt(cur+1, "E", "I", "Y") || m.stringAt(cur-1, "AGGI", "OGGI") {
+		if m.germanic() || m.stringAt(cur+1, "ET") {
+			// obvious germanic
+			m.add("K")
+		} else if m.stringAt(cur+1, "IER") && cur+4 >= m.length {
+			// always soft if french ending
+			m.add("J")
+		} else {
+			m.addAlt("J", "K")
+		}
+		m.current += 2
+		return
+	}
+
+	m.add("K")
+	m.skipDouble('G')
+}
+
+func (m *metaphone) encodeJ() {
+	cur := m.current
+
+	// obvious spanish, jose, san jacinto
+	if m.stringAt(cur, "JOSE") || m.stringAt(0, "SAN ") {
+		if (cur == 0 && cur+4 >= m.length) || m.stringAt(0, "SAN ") {
+			m.add("H")
+		} else {
+			m.addAlt("J", "H")
+		}
+		m.current++
+		return
+	}
+
+	if cur == 0 {
+		// Yankelovich, Jankelowicz
+		m.addAlt("J", "A")
+	} else if m.isVowel(cur-1) && !m.slavoGermanic() && (m.at(cur+1) == 'A' || m.at(cur+1) == 'O') {
+		// spanish pronunciation of e.g. bajador
+		m.addAlt("J", "H")
+	} else if cur == m.last {
+		m.addAlt("J", " ")
+	} else if !m.stringAt(cur+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.stringAt(cur-1, "S", "K", "L") {
+		m.add("J")
+	}
+	m.skipDouble('J')
+}
+
+func (m *metaphone) encodeS() {
+	cur := m.current
+
+	// island, isle, carlisle, carlysle
+	if m.stringAt(cur-1, "ISL", "YSL") {
+		m.current++
+		return
+	}
+	// sugar
+	if cur == 0 && m.stringAt(cur, "SUGAR") {
+		m.addAlt("X", "S")
+		m.current++
+		return
+	}
+	if m.stringAt(cur, "SH") {
+		// germanic
+		if m.stringAt(cur+1, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		m.current += 2
+		return
+	}
+	// italian and armenian
+	if m.stringAt(cur, "SIO", "SIA") || m.stringAt(cur, "SIAN") {
+		if !m.slavoGermanic() {
+			m.addAlt("S", "X")
+		} else {
+			m.add("S")
+		}
+		m.current += 3
+		return
+	}
+	// german and anglicisations, e.g. smith matches schmidt, snider matches
+	// schneider, also -sz- in slavic languages
+	if (cur == 0 && m.stringAt(cur+1, "M", "N", "L", "W")) || m.stringAt(cur+1, "Z") {
+		m.addAlt("S", "X")
+		m.skipDouble('Z')
+		return
+	}
+	if m.stringAt(cur, "SC") {
+		// Schlesinger's rule
+		if m.at(cur+2) == 'H' {
+			if m.stringAt(cur+3, "OO", "ER", "EN", "UY", "ED", "EM") {
+				// dutch origin, e.g. school, schooner, schermerhorn, schenker
+				if m.stringAt(cur+3, "ER", "EN") {
+					m.addAlt("X", "SK")
+				} else {
+					m.add("SK")
+				}
+			} else if cur == 0 && !m.isVowel(3) && m.at(3) != 'W' {
+				m.addAlt("X", "S")
+			} else {
+				m.add("X")
+			}
+		} else if m.stringAt(cur+2, "I", "E", "Y") {
+			m.add("S")
+		} else {
+			m.add("SK")
+		}
+		m.current += 3
+		return
+	}
+
+	// french, e.g. resnais, artois
+	if cur == m.last && m.stringAt(cur-2, "AI", "OI") {
+		m.addAlt("", "S")
+	} else {
+		m.add("S")
+	}
+	if m.stringAt(cur+1, "S", "Z") {
+		m.current += 2
+	} else {
+		m.current++
+	}
+}
+
+func (m *metaphone) encodeT() {
+	cur := m.current
+
+	if m.stringAt(cur, "TION") || m.stringAt(cur, "TIA", "TCH") {
+		m.add("X")
+		m.current += 3
+		return
+	}
+	if m.stringAt(cur, "TH") || m.stringAt(cur, "TTH") {
+		// thomas, thames or germanic
+		if m.stringAt(cur+2, "OM", "AM") || m.germanic() {
+			m.add("T")
+		} else {
+			m.addAlt("0", "T")
+		}
+		m.current += 2
+		return
+	}
+	if m.stringAt(cur+1, "T", "D") {
+		m.current += 2
+	} else {
+		m.current++
+	}
+	m.add("T")
+}
+
+func (m *metaphone) encodeW() {
+	cur := m.current
+
+	// can also be in the middle of a word
+	if m.stringAt(cur, "WR") {
+		m.add("R")
+		m.current += 2
+		return
+	}
+
+	if cur == 0 && (m.isVowel(cur+1) || m.stringAt(cur, "WH")) {
+		if m.isVowel(cur + 1) {
+			// Wasserman matches Vasserman
+			m.addAlt("A", "F")
+		} else {
+			// Uomo matches Womo
+			m.add("A")
+		}
+	}
+
+	// Arnow matches Arnoff
+	if (cur == m.last && m.isVowel(cur-1)) || m.stringAt(cur-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		m.stringAt(0, "SCH") {
+		m.addAlt("", "F")
+		m.current++
+		return
+	}
+
+	// polish, e.g. filipowicz
+	if m.stringAt(cur, "WICZ", "WITZ") {
+		m.addAlt("TS", "FX")
+		m.current += 4
+		return
+	}
+
+	m.current++
+}
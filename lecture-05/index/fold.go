@@ -0,0 +1,51 @@
+package index
+
+import "strings"
+
+// foldings maps accented Latin letters to ASCII. German umlauts and ß use
+// their usual transliteration, so "Fürstin" becomes "Fuerstin".
+var foldings = map[rune]string{
+	'ä': "ae", 'ö': "oe", 'ü': "ue", 'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue", 'ß': "ss",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Å': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'æ': "ae", 'Æ': "Ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c",
+	'Ç': "C", 'Ć': "C", 'Č': "C", 'Ĉ': "C", 'Ċ': "C",
+	'ď': "d", 'đ': "d", 'ð': "d", 'Ď': "D", 'Đ': "D", 'Ð': "D",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E", 'Ĕ': "E", 'Ė': "E", 'Ę': "E", 'Ě': "E",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g", 'Ĝ': "G", 'Ğ': "G", 'Ġ': "G", 'Ģ': "G",
+	'ĥ': "h", 'ħ': "h", 'Ĥ': "H", 'Ħ': "H",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ĩ': "I", 'Ī': "I", 'Ĭ': "I", 'Į': "I", 'İ': "I",
+	'ĵ': "j", 'Ĵ': "J", 'ķ': "k", 'Ķ': "K",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ł': "l", 'Ĺ': "L", 'Ļ': "L", 'Ľ': "L", 'Ł': "L",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n", 'Ñ': "N", 'Ń': "N", 'Ņ': "N", 'Ň': "N",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ø': "O", 'Ō': "O", 'Ŏ': "O", 'Ő': "O",
+	'œ': "oe", 'Œ': "Oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r", 'Ŕ': "R", 'Ŗ': "R", 'Ř': "R",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'Ś': "S", 'Ŝ': "S", 'Ş': "S", 'Š': "S",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'þ': "th", 'Ţ': "T", 'Ť': "T", 'Ŧ': "T", 'Þ': "Th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ũ': "U", 'Ū': "U", 'Ŭ': "U", 'Ů': "U", 'Ű': "U", 'Ų': "U",
+	'ŵ': "w", 'Ŵ': "W",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y", 'Ý': "Y", 'Ÿ': "Y", 'Ŷ': "Y",
+	'ź': "z", 'ż': "z", 'ž': "z", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+}
+
+// FoldAccents replaces accented Latin letters by their ASCII transliteration,
+// e.g. "Amélie" by "Amelie" and "Fürstin" by "Fuerstin". Other characters are
+// kept as they are.
+func FoldAccents(raw string) string {
+	var b strings.Builder
+	b.Grow(len(raw))
+	for _, r := range raw {
+		if folded, ok := foldings[r]; ok {
+			b.WriteString(folded)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
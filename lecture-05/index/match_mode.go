@@ -0,0 +1,166 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MatchMode selects the distance FindMatchesWithMode compares x and y with.
+type MatchMode int
+
+const (
+	// PrefixMatch uses the prefix edit distance, for autocompletion.
+	PrefixMatch MatchMode = iota
+	// LevenshteinMatch uses the edit distance of the complete words, for
+	// spelling correction.
+	LevenshteinMatch
+	// DamerauMatch is LevenshteinMatch where swapping two adjacent characters
+	// counts as one edit.
+	DamerauMatch
+)
+
+var ErrUnknownMatchMode = errors.New("unknown match mode")
+
+func (m MatchMode) String() string {
+	switch m {
+	case PrefixMatch:
+		return "prefix"
+	case LevenshteinMatch:
+		return "levenshtein"
+	case DamerauMatch:
+		return "damerau"
+	default:
+		return fmt.Sprintf("MatchMode(%d)", int(m))
+	}
+}
+
+func ParseMatchMode(s string) (MatchMode, error) {
+	for _, m := range []MatchMode{PrefixMatch, LevenshteinMatch, DamerauMatch} {
+		if m.String() == s {
+			return m, nil
+		}
+	}
+	return 0, ErrUnknownMatchMode
+}
+
+// minCommonQGrams returns the number of q-grams x and y must have in common
+// if their distance is at most δ. x and y are padded with q-1 characters on
+// both sides.
+//
+// An insertion, deletion or substitution changes at most q q-grams, so for the
+// edit distance comm(x, y) ≥ max(|x|, |y|) - 1 - (δ - 1) * q. A transposition
+// changes at most q + 1 q-grams, which gives max(|x|, |y|) + q - 1 - δ(q + 1).
+// For the prefix edit distance, the q-grams of x overlapping its right padding
+// need not be in y, see FindMatches.
+func (m MatchMode) minCommonQGrams(q, lenX, lenY, delta int) int {
+	switch m {
+	case LevenshteinMatch:
+		return maxInt(lenX, lenY) - 1 - (delta-1)*q
+	case DamerauMatch:
+		return maxInt(lenX, lenY) + q - 1 - delta*(q+1)
+	default:
+		return lenX - 1 - delta*q
+	}
+}
+
+// distance returns the distance of x and y if it is at most δ, δ + 1 otherwise.
+func (m MatchMode) distance(x, y string, delta int) int {
+	switch m {
+	case LevenshteinMatch:
+		return EditDistance(x, y, delta)
+	case DamerauMatch:
+		return DamerauEditDistance(x, y, delta)
+	default:
+		return BandedPrefixEditDistance(x, y, delta)
+	}
+}
+
+// distanceTo returns the distance function of x for comparing it to many y,
+// the prefix edit distance then uses a precomputed PEDPattern.
+func (m MatchMode) distanceTo(x string) func(y string, delta int) int {
+	if m == PrefixMatch {
+		return NewPEDPattern(x).PrefixEditDistance
+	}
+	return func(y string, delta int) int {
+		return m.distance(x, y, delta)
+	}
+}
+
+// EditDistance computes the Levenshtein distance of x and y on runes and
+// returns it if it is smaller or equal to δ. Otherwise it returns δ + 1.
+func EditDistance(x, y string, delta int) int {
+	return editDistance([]rune(x), []rune(y), delta, false)
+}
+
+// DamerauEditDistance is EditDistance where a transposition of two adjacent
+// characters counts as one edit (optimal string alignment, a character is not
+// edited again after being transposed).
+func DamerauEditDistance(x, y string, delta int) int {
+	return editDistance([]rune(x), []rune(y), delta, true)
+}
+
+func editDistance(x, y []rune, delta int, transpositions bool) int {
+	if absInt(len(x)-len(y)) > delta {
+		return delta + 1
+	}
+
+	// three rows suffice, the one before the previous row is needed for
+	// transpositions
+	prevPrev := make([]int, len(y)+1)
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	prevMin := 0
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+		rowMin := i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			d := minInt(prev[j-1]+cost, minInt(prev[j]+1, curr[j-1]+1))
+			if transpositions && i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				d = minInt(d, prevPrev[j-2]+1)
+			}
+			curr[j] = d
+			rowMin = minInt(rowMin, d)
+		}
+
+		// NOTE: values never drop below the minimum of the last two rows
+		if rowMin > delta && prevMin > delta {
+			return delta + 1
+		}
+		prevMin = rowMin
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	if prev[len(y)] > delta {
+		return delta + 1
+	}
+	return prev[len(y)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
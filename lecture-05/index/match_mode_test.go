@@ -0,0 +1,134 @@
+package index
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMatchMode(t *testing.T) {
+	for _, mode := range []MatchMode{PrefixMatch, LevenshteinMatch, DamerauMatch} {
+		got, err := ParseMatchMode(mode.String())
+		assert.NoError(t, err)
+		assert.Equal(t, mode, got)
+	}
+
+	_, err := ParseMatchMode("soundex")
+	assert.Equal(t, ErrUnknownMatchMode, err)
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		givenX      string
+		givenY      string
+		givenDelta  int
+		wantED      int
+		wantDamerau int
+	}{
+		{"frei", "frei", 0, 0, 0},
+		{"frei", "freiburg", 4, 4, 4},
+		{"frei", "freiburg", 3, 4, 4},
+		{"frei", "brei", 1, 1, 1},
+		{"frie", "frei", 2, 2, 1},
+		{"ca", "abc", 3, 3, 3},
+		{"freiburg", "stuttgart", 2, 3, 3},
+		{"fürstin", "fuerstin", 2, 2, 2},
+		{"", "abc", 3, 3, 3},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantED, EditDistance(tt.givenX, tt.givenY, tt.givenDelta), "%s %s", tt.givenX, tt.givenY)
+		assert.Equal(t, tt.wantDamerau, DamerauEditDistance(tt.givenX, tt.givenY, tt.givenDelta), "%s %s", tt.givenX, tt.givenY)
+	}
+}
+
+func TestQGramIndex_FindMatchesWithMode(t *testing.T) {
+	tests := []struct {
+		givenMode  MatchMode
+		givenX     string
+		givenDelta int
+		wantNames  []string
+	}{
+		{PrefixMatch, "fre", 0, []string{"frei"}},
+		{LevenshteinMatch, "fre", 0, nil},
+		{LevenshteinMatch, "frei", 0, []string{"frei"}},
+		{LevenshteinMatch, "frie", 1, nil},
+		{DamerauMatch, "frie", 1, []string{"frei"}},
+		{LevenshteinMatch, "freu", 1, []string{"frei"}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			q := NewQGramIndex(3)
+			assert.NoError(t, q.BuildFromFile("example.tsv"))
+			matches, _ := q.FindMatchesWithMode(tt.givenX, tt.givenDelta, tt.givenMode)
+			var names []string
+			for _, match := range RankMatches(matches) {
+				names = append(names, match.Entity.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+// The q-gram filter must not lose any match, so FindMatchesWithMode has to
+// find the same entities as computing the distance to every entity. Entities
+// without a common q-gram are not candidates at all, which only matters when
+// the bound is not positive.
+func TestQGramIndex_FindMatchesWithMode_Filter(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	randomWord := func() string {
+		b := make([]byte, 1+r.Intn(8))
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+		return string(b)
+	}
+
+	dir, err := ioutil.TempDir("", "qgram")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "entities.tsv")
+	content := "name\tscore\tdescription\n"
+	for i := 0; i < 300; i++ {
+		content += fmt.Sprintf("%s\t%d\tword %d\n", randomWord(), i, i)
+	}
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+
+	for _, q := range []int{2, 3} {
+		qi := NewQGramIndex(q)
+		assert.NoError(t, qi.BuildFromFile(filename))
+		for _, mode := range []MatchMode{PrefixMatch, LevenshteinMatch, DamerauMatch} {
+			for i := 0; i < 50; i++ {
+				x, delta := randomWord(), r.Intn(3)
+
+				var want []int
+				for _, entity := range qi.EntityMap {
+					if mode.minCommonQGrams(q, len(x), len(entity.Name), delta) > 0 &&
+						mode.distance(x, entity.Name, delta) <= delta {
+						want = append(want, entity.Score)
+					}
+				}
+				sort.Ints(want)
+
+				matches, _ := qi.FindMatchesWithMode(x, delta, mode)
+				var got []int
+				for _, match := range matches {
+					assert.Equal(t, match.PED, mode.distance(x, match.Entity.Name, delta))
+					if mode.minCommonQGrams(q, len(x), len(match.Entity.Name), delta) > 0 {
+						got = append(got, match.Entity.Score)
+					}
+				}
+				sort.Ints(got)
+
+				assert.Equal(t, want, got, "q=%d mode=%s x=%s delta=%d", q, mode, x, delta)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package index
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MultiWordIndex matches queries of several keywords against the single words
+// of the entity names, instead of gluing all words of the query and of the
+// names together like Normalize does.
+type MultiWordIndex struct {
+	Entities *QGramIndex
+	// Words indexes the distinct normalized words of all entity names, the
+	// Score of a word is the number of entities containing it.
+	Words *QGramIndex
+	// WordEntities maps the id of a word to the sorted ids of the entities
+	// containing it.
+	WordEntities map[int][]int
+	// WordMode is used for all keywords but the last one, which is matched as
+	// a prefix.
+	WordMode MatchMode
+
+	wordIds map[string]int
+}
+
+// NewMultiWordIndex builds the word index for the entities of the given index.
+func NewMultiWordIndex(entities *QGramIndex) *MultiWordIndex {
+	m := &MultiWordIndex{
+		Entities:     entities,
+		Words:        NewQGramIndex(entities.Q),
+		WordEntities: make(map[int][]int),
+		WordMode:     LevenshteinMatch,
+		wordIds:      make(map[string]int),
+	}
+	m.Words.FoldAccents = entities.FoldAccents
+
+	entityIds := make([]int, 0, len(entities.EntityMap))
+	for entityId := range entities.EntityMap {
+		entityIds = append(entityIds, entityId)
+	}
+	sort.Ints(entityIds)
+
+	for _, entityId := range entityIds {
+		// the words of the aliases count as words of the entity
+		var words []string
+		for _, name := range append([]string{entities.EntityMap[entityId].Name}, entities.Aliases(entityId)...) {
+			words = append(words, SplitWords(name)...)
+		}
+		for _, word := range words {
+			word = entities.normalize(word)
+			wordId, ok := m.wordIds[word]
+			if !ok {
+				wordId = m.Words.AddEntity(Entity{Name: word})
+				m.wordIds[word] = wordId
+			}
+
+			// NOTE: a word may occur several times in the same name
+			list := m.WordEntities[wordId]
+			if len(list) > 0 && list[len(list)-1] == entityId {
+				continue
+			}
+			m.WordEntities[wordId] = append(list, entityId)
+
+			entity := m.Words.EntityMap[wordId]
+			entity.Score += 1
+			m.Words.EntityMap[wordId] = entity
+		}
+	}
+	return m
+}
+
+// SplitWords splits s into words at every character that is not a letter or a
+// digit.
+func SplitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+type MultiWordMatch struct {
+	Entity Entity
+	// PED is the sum of the distances of all keywords to their closest word of
+	// the entity name.
+	PED int
+	// Words are the closest words of the entity name, one per keyword.
+	Words []string
+}
+
+// FindMatches finds all entities that match every keyword of the query, delta
+// returns the δ for each keyword. Every keyword is matched fuzzily against the
+// words of the entity names with WordMode, except the last one, which is
+// matched as a prefix since the user may still be typing it.
+func (m *MultiWordIndex) FindMatches(query string, delta DeltaPolicy) (matches []MultiWordMatch, numPEDComputations int) {
+	keywords := SplitWords(query)
+	if len(keywords) == 0 {
+		return
+	}
+
+	var candidates map[int]*MultiWordMatch
+	for i, keyword := range keywords {
+		mode := m.WordMode
+		if i == len(keywords)-1 {
+			mode = PrefixMatch
+		}
+
+		wordMatches, num := m.Words.FindMatchesWithMode(keyword, delta(keyword), mode)
+		numPEDComputations += num
+
+		// the closest word of each entity for this keyword
+		closest := make(map[int]EntityPEDPair)
+		for _, wordMatch := range wordMatches {
+			wordId := m.wordIds[wordMatch.Entity.Name]
+			for _, entityId := range m.WordEntities[wordId] {
+				if c, ok := closest[entityId]; ok && c.PED <= wordMatch.PED {
+					continue
+				}
+				if candidates != nil && candidates[entityId] == nil {
+					continue
+				}
+				closest[entityId] = wordMatch
+			}
+		}
+
+		next := make(map[int]*MultiWordMatch, len(closest))
+		for entityId, wordMatch := range closest {
+			match := candidates[entityId]
+			if match == nil {
+				match = &MultiWordMatch{Entity: m.Entities.EntityMap[entityId]}
+			}
+			match.PED += wordMatch.PED
+			match.Words = append(match.Words, wordMatch.Entity.Name)
+			next[entityId] = match
+		}
+		candidates = next
+	}
+
+	entityIds := make([]int, 0, len(candidates))
+	for entityId := range candidates {
+		entityIds = append(entityIds, entityId)
+	}
+	sort.Ints(entityIds)
+	for _, entityId := range entityIds {
+		matches = append(matches, *candidates[entityId])
+	}
+	return
+}
+
+// RankMultiWordMatches sorts by summed PED ascending, then by Score descending.
+func RankMultiWordMatches(matches []MultiWordMatch) (sorted []MultiWordMatch) {
+	sorted = make([]MultiWordMatch, len(matches))
+	copy(sorted, matches)
+	sort.Slice(sorted, func(i, j int) bool {
+		mi, mj := sorted[i], sorted[j]
+		if mi.PED != mj.PED {
+			return mi.PED < mj.PED
+		}
+
+		return mi.Entity.Score > mj.Entity.Score
+	})
+	return
+}
@@ -0,0 +1,92 @@
+package index
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		givenStr  string
+		wantWords []string
+	}{
+		{"The Big Lebowski", []string{"The", "Big", "Lebowski"}},
+		{"Big, Big World!", []string{"Big", "Big", "World"}},
+		{"Amélie", []string{"Amélie"}},
+		{" ,", []string{}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantWords, SplitWords(tt.givenStr))
+	}
+}
+
+func TestNewMultiWordIndex(t *testing.T) {
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_multi_word.tsv"))
+	m := NewMultiWordIndex(q)
+
+	entityIds := make(map[string][]int)
+	scores := make(map[string]int)
+	for wordId, entity := range m.Words.EntityMap {
+		entityIds[entity.Name] = m.WordEntities[wordId]
+		scores[entity.Name] = entity.Score
+	}
+	assert.Equal(t, []int{1, 2}, entityIds["the"])
+	assert.Equal(t, []int{1, 2, 3, 4}, entityIds["big"])
+	assert.Equal(t, 4, scores["big"])
+	assert.Equal(t, []int{4}, entityIds["world"])
+	assert.Len(t, m.Words.EntityMap, 6)
+}
+
+func TestMultiWordIndex_FindMatches(t *testing.T) {
+	tests := []struct {
+		givenQuery string
+		givenDelta int
+		wantNames  []string
+		wantPEDs   []int
+	}{
+		{"the BIG lebauski", 2, []string{"The Big Lebowski"}, []int{2}},
+		// the last keyword is a prefix
+		{"big lebo", 0, []string{"The Big Lebowski"}, []int{0}},
+		{"big", 0, []string{"The Big Lebowski", "Big Fish", "The Big Sleep", "Big, Big World"}, []int{0, 0, 0, 0}},
+		// all keywords must match, in any order
+		{"fish bg", 1, []string{"Big Fish"}, []int{1}},
+		{"the fish", 1, nil, nil},
+		{"", 1, nil, nil},
+	}
+
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_multi_word.tsv"))
+	m := NewMultiWordIndex(q)
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			matches, _ := m.FindMatches(tt.givenQuery, func(string) int { return tt.givenDelta })
+			var names []string
+			var peds []int
+			for _, match := range RankMultiWordMatches(matches) {
+				names = append(names, match.Entity.Name)
+				peds = append(peds, match.PED)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantPEDs, peds)
+		})
+	}
+}
+
+func TestMultiWordIndex_FindMatches_Damerau(t *testing.T) {
+	q := NewQGramIndex(3)
+	assert.NoError(t, q.BuildFromFile("example_multi_word.tsv"))
+	m := NewMultiWordIndex(q)
+	m.WordMode = DamerauMatch
+
+	matches, _ := m.FindMatches("teh big", func(string) int { return 1 })
+	assert.Len(t, matches, 2)
+	for _, match := range matches {
+		assert.Equal(t, 1, match.PED)
+		assert.Equal(t, []string{"the", "big"}, match.Words)
+	}
+}
@@ -0,0 +1,197 @@
+package index
+
+import (
+	"math/bits"
+)
+
+// PEDPattern precomputes the match vectors of a pattern x, so the prefix edit
+// distance of x to many strings y can be computed with the bit-parallel
+// algorithm of Myers in the formulation of Hyyrö: a column of the DP table is
+// stored as its vertical differences, 64 rows per word. Patterns longer than 64
+// runes use several words per column.
+//
+// NOTE: a PEDPattern holds scratch space and must not be used by several
+// goroutines at the same time.
+type PEDPattern struct {
+	m         int
+	numBlocks int
+	peq       map[rune][]uint64
+	zero      []uint64
+	// the vertical differences of the current column, +1 in pv, -1 in mv
+	pv, mv []uint64
+}
+
+func NewPEDPattern(x string) *PEDPattern {
+	runes := []rune(x)
+	p := &PEDPattern{
+		m:         len(runes),
+		numBlocks: (len(runes) + 63) / 64,
+		peq:       make(map[rune][]uint64),
+	}
+	p.zero = make([]uint64, p.numBlocks)
+	p.pv = make([]uint64, p.numBlocks)
+	p.mv = make([]uint64, p.numBlocks)
+	for i, r := range runes {
+		eq, ok := p.peq[r]
+		if !ok {
+			eq = make([]uint64, p.numBlocks)
+			p.peq[r] = eq
+		}
+		eq[i/64] |= 1 << uint(i%64)
+	}
+	return p
+}
+
+// PrefixEditDistance returns the same as PrefixEditDistance(x, y, δ).
+func (p *PEDPattern) PrefixEditDistance(y string, delta int) int {
+	// NOTE: ped = 0 for empty word
+	if p.m == 0 {
+		return 0
+	}
+
+	// the first column is 0, 1, ..., m
+	for b := range p.pv {
+		p.pv[b], p.mv[b] = ^uint64(0), 0
+	}
+	lastMask := ^uint64(0) >> uint(64*p.numBlocks-p.m)
+	score, best := p.m, p.m
+
+	// columns beyond m + δ have a distance of more than δ
+	maxCols := p.m + delta
+	j := 0
+	for _, c := range y {
+		j++
+		if j > maxCols {
+			break
+		}
+
+		eq, ok := p.peq[c]
+		if !ok {
+			eq = p.zero
+		}
+		// the first row is 0, 1, ..., so it grows by one per column
+		hin := 1
+		for b := 0; b < p.numBlocks; b++ {
+			highBit := uint64(1) << 63
+			if b == p.numBlocks-1 {
+				highBit = uint64(1) << uint((p.m-1)%64)
+			}
+			hin = p.advanceBlock(b, eq[b], hin, highBit)
+		}
+		score += hin
+		if score < best {
+			best = score
+		}
+
+		// every path to a later cell of the last row crosses this column, so
+		// the column minimum bounds all later distances. It is at least the
+		// last row minus the number of +1 differences above it.
+		var ups int
+		for b := 0; b < p.numBlocks-1; b++ {
+			ups += bits.OnesCount64(p.pv[b])
+		}
+		ups += bits.OnesCount64(p.pv[p.numBlocks-1] & lastMask)
+		if lowerBound := score - ups; lowerBound >= best || lowerBound > delta {
+			break
+		}
+	}
+
+	if best > delta {
+		return delta + 1
+	}
+	return best
+}
+
+// advanceBlock computes the next column of block b given the horizontal
+// difference hin entering it from above, and returns the horizontal difference
+// leaving it at highBit.
+func (p *PEDPattern) advanceBlock(b int, eq uint64, hin int, highBit uint64) (hout int) {
+	pv, mv := p.pv[b], p.mv[b]
+	xv := eq | mv
+	if hin < 0 {
+		eq |= 1
+	}
+	xh := (((eq & pv) + pv) ^ pv) | eq
+	ph := mv | ^(xh | pv)
+	mh := pv & xh
+
+	if ph&highBit != 0 {
+		hout = 1
+	} else if mh&highBit != 0 {
+		hout = -1
+	}
+
+	ph <<= 1
+	mh <<= 1
+	if hin < 0 {
+		mh |= 1
+	} else if hin > 0 {
+		ph |= 1
+	}
+	p.pv[b] = mh | ^(xv | ph)
+	p.mv[b] = ph & xv
+	return
+}
+
+// BandedPrefixEditDistance returns the same as PrefixEditDistance(x, y, δ),
+// but only fills the cells of the DP table within δ of the diagonal, in two
+// rows, and stops as soon as a row has no cell of at most δ. It needs no
+// precomputation, unlike PEDPattern.
+func BandedPrefixEditDistance(xStr, yStr string, delta int) int {
+	x, y := []rune(xStr), []rune(yStr)
+
+	// NOTE: ped = 0 for empty word
+	if len(x) == 0 {
+		return 0
+	}
+
+	numCols := len(x) + delta + 1
+	if numCols > len(y)+1 {
+		numCols = len(y) + 1
+	}
+
+	// cells outside the band are more than δ
+	inf := delta + 1
+	prev, curr := make([]int, numCols), make([]int, numCols)
+	for j := range prev {
+		prev[j] = minInt(j, inf)
+	}
+
+	for i := 1; i <= len(x); i++ {
+		lo, hi := maxInt(0, i-delta), minInt(numCols-1, i+delta)
+		if lo > 0 {
+			curr[lo-1] = inf
+		}
+		rowMin := inf
+		for j := lo; j <= hi; j++ {
+			var d int
+			if j == 0 {
+				d = i
+			} else if x[i-1] == y[j-1] {
+				d = prev[j-1]
+			} else {
+				d = minInt(prev[j-1], curr[j-1]) + 1
+				if j <= i-1+delta {
+					d = minInt(d, prev[j]+1)
+				}
+			}
+			d = minInt(d, inf)
+			curr[j] = d
+			rowMin = minInt(rowMin, d)
+		}
+		if hi+1 < numCols {
+			curr[hi+1] = inf
+		}
+
+		if rowMin > delta {
+			return delta + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	ped := inf
+	for j := maxInt(0, len(x)-delta); j < numCols; j++ {
+		ped = minInt(ped, prev[j])
+	}
+	return ped
+}
@@ -0,0 +1,115 @@
+package index
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPEDPattern_PrefixEditDistance(t *testing.T) {
+	tests := []struct {
+		givenX     string
+		givenY     string
+		givenDelta int
+		wantPED    int
+	}{
+		{"frei", "frei", 0, 0},
+		{"frei", "freiburg", 0, 0},
+		{"frei", "breifurg", 1, 1},
+		{"freiburg", "stuttgart", 2, 3},
+		{"", "frei", 0, 0},
+		{"frei", "", 4, 4},
+		{"fürs", "fürstin", 0, 0},
+		{strings.Repeat("ab", 50), strings.Repeat("ab", 60), 0, 0},
+		{strings.Repeat("ab", 50), "x" + strings.Repeat("ab", 60), 2, 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantPED, NewPEDPattern(tt.givenX).PrefixEditDistance(tt.givenY, tt.givenDelta), "%s %s", tt.givenX, tt.givenY)
+		assert.Equal(t, tt.wantPED, BandedPrefixEditDistance(tt.givenX, tt.givenY, tt.givenDelta), "%s %s", tt.givenX, tt.givenY)
+	}
+}
+
+// Both must agree exactly with PrefixEditDistance, for patterns of one and of
+// several words and for runes of several bytes.
+func TestPrefixEditDistance_Differential(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	alphabet := []rune("abcü")
+	randomWord := func(maxLen int) string {
+		w := make([]rune, r.Intn(maxLen+1))
+		for i := range w {
+			w[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(w)
+	}
+
+	for _, maxLen := range []int{8, 70, 140} {
+		for i := 0; i < 2000; i++ {
+			x, y := randomWord(maxLen), randomWord(maxLen)
+			if r.Intn(2) == 0 {
+				// y similar to x, so that small distances are common
+				y = mutate(r, x, alphabet, r.Intn(4)) + randomWord(maxLen/4)
+			}
+			delta := r.Intn(maxLen/2 + 1)
+
+			want := PrefixEditDistance(x, y, delta)
+			msg := fmt.Sprintf("x=%s y=%s delta=%d", x, y, delta)
+			assert.Equal(t, want, NewPEDPattern(x).PrefixEditDistance(y, delta), msg)
+			assert.Equal(t, want, BandedPrefixEditDistance(x, y, delta), msg)
+		}
+	}
+}
+
+// A PEDPattern can be reused for many y.
+func TestPEDPattern_Reuse(t *testing.T) {
+	p := NewPEDPattern("freiburg")
+	for _, y := range []string{"stuttgart", "freiburg", "breisach", "frei", ""} {
+		assert.Equal(t, PrefixEditDistance("freiburg", y, 3), p.PrefixEditDistance(y, 3), y)
+	}
+}
+
+func mutate(r *rand.Rand, s string, alphabet []rune, numEdits int) string {
+	w := []rune(s)
+	for e := 0; e < numEdits; e++ {
+		pos := r.Intn(len(w) + 1)
+		switch r.Intn(3) {
+		case 0:
+			w = append(w[:pos], append([]rune{alphabet[r.Intn(len(alphabet))]}, w[pos:]...)...)
+		case 1:
+			if pos < len(w) {
+				w = append(w[:pos], w[pos+1:]...)
+			}
+		default:
+			if pos < len(w) {
+				w[pos] = alphabet[r.Intn(len(alphabet))]
+			}
+		}
+	}
+	return string(w)
+}
+
+func BenchmarkPrefixEditDistance(b *testing.B) {
+	x, y, delta := "the big lebauski", "thebiglebowskiisa1998comedyfilm", 4
+	x = Normalize(x)
+
+	b.Run("Matrix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PrefixEditDistance(x, y, delta)
+		}
+	})
+	b.Run("Banded", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BandedPrefixEditDistance(x, y, delta)
+		}
+	})
+	b.Run("BitParallel", func(b *testing.B) {
+		p := NewPEDPattern(x)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.PrefixEditDistance(y, delta)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package index
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// PhoneticEncoder maps a word to the keys of its pronunciation, so words
+// that sound alike share a key even if they are far apart in edit distance,
+// e.g. "Shwarzenegger" and "Schwarzenegger".
+type PhoneticEncoder interface {
+	Name() string
+	// Keys returns the keys of a single word, none if it has no letters.
+	Keys(word string) []string
+}
+
+var ErrUnknownPhoneticEncoder = errors.New("unknown phonetic encoder")
+
+type SoundexEncoder struct{}
+
+func (SoundexEncoder) Name() string { return "soundex" }
+
+func (SoundexEncoder) Keys(word string) []string {
+	return nonEmptyKeys(Soundex(word))
+}
+
+// DoubleMetaphoneEncoder returns the primary and, if different, the alternate
+// code as keys.
+type DoubleMetaphoneEncoder struct{}
+
+func (DoubleMetaphoneEncoder) Name() string { return "double-metaphone" }
+
+func (DoubleMetaphoneEncoder) Keys(word string) []string {
+	primary, alternate := DoubleMetaphone(word)
+	if alternate == primary {
+		return nonEmptyKeys(primary)
+	}
+	return nonEmptyKeys(primary, alternate)
+}
+
+type ColognePhoneticsEncoder struct{}
+
+func (ColognePhoneticsEncoder) Name() string { return "cologne" }
+
+func (ColognePhoneticsEncoder) Keys(word string) []string {
+	return nonEmptyKeys(ColognePhonetics(word))
+}
+
+func ParsePhoneticEncoder(s string) (PhoneticEncoder, error) {
+	for _, e := range []PhoneticEncoder{SoundexEncoder{}, DoubleMetaphoneEncoder{}, ColognePhoneticsEncoder{}} {
+		if e.Name() == s {
+			return e, nil
+		}
+	}
+	return nil, ErrUnknownPhoneticEncoder
+}
+
+func nonEmptyKeys(keys ...string) (ret []string) {
+	for _, key := range keys {
+		if key != "" {
+			ret = append(ret, key)
+		}
+	}
+	return
+}
+
+// phoneticLetters returns the upper case ASCII letters of the word after
+// folding accents, the encoders ignore everything else.
+func phoneticLetters(word string) (letters []rune) {
+	for _, r := range strings.ToUpper(FoldAccents(word)) {
+		if r >= 'A' && r <= 'Z' {
+			letters = append(letters, r)
+		}
+	}
+	return
+}
+
+// addPhoneticKeys adds the entity to the phonetic list of every key of the
+// words of its name. The lists stay sorted and hold an entity once, also when
+// an alias of an earlier entity is added.
+func (q *QGramIndex) addPhoneticKeys(entityId int, name string) {
+	for _, word := range SplitWords(name) {
+		for _, key := range q.PhoneticEncoder.Keys(word) {
+			list := q.PhoneticLists[key]
+			i := sort.SearchInts(list, entityId)
+			if i < len(list) && list[i] == entityId {
+				continue
+			}
+			list = append(list, 0)
+			copy(list[i+1:], list[i:])
+			list[i] = entityId
+			q.PhoneticLists[key] = list
+		}
+	}
+}
+
+// FindMatchesWithPhonetics adds to the matches of FindMatchesWithMode the
+// entities that sound like x: every word of x shares a phonetic key with a
+// word of the name or of an alias. Their PED is the sum of the edit distances
+// of the words of x to the closest of these words, so they can be ranked
+// together with the other matches by RankMatches, and may exceed δ. An entity
+// found both ways is then collapsed to the smaller distance.
+// numPhoneticMatches counts the entities only found by their sound.
+//
+// Without a PhoneticEncoder it returns the same as FindMatchesWithMode.
+func (q *QGramIndex) FindMatchesWithPhonetics(x string, delta int, mode MatchMode) (matches []EntityPEDPair, numPEDComputations, numPhoneticMatches int) {
+	found := make(map[int]bool)
+	numPEDComputations = q.forEachMatch(x, delta, mode, func(entityId int, alias string, ped int) {
+		found[entityId] = true
+		matches = append(matches, EntityPEDPair{
+			EntityId: entityId,
+			Entity:   q.EntityMap[entityId],
+			PED:      ped,
+			Alias:    alias,
+		})
+	})
+
+	if q.PhoneticEncoder == nil {
+		return
+	}
+
+	var words []string
+	for _, word := range SplitWords(x) {
+		if len(q.PhoneticEncoder.Keys(word)) > 0 {
+			words = append(words, word)
+		}
+	}
+
+	for _, entityId := range q.phoneticCandidates(words) {
+		entity := q.EntityMap[entityId]
+		matched := false
+		for i, name := range append([]string{entity.Name}, q.Aliases(entityId)...) {
+			ped, ok, numEDs := q.phoneticDistance(words, SplitWords(name))
+			numPEDComputations += numEDs
+			if !ok {
+				continue
+			}
+
+			match := EntityPEDPair{EntityId: entityId, Entity: entity, PED: ped}
+			if i > 0 {
+				match.Alias = name
+			}
+			matches = append(matches, match)
+			matched = true
+		}
+		if matched && !found[entityId] {
+			numPhoneticMatches += 1
+		}
+	}
+	return
+}
+
+// phoneticDistance sums the edit distances of the words to their closest
+// name words with a common key, ok is false if a word has no such name word.
+func (q *QGramIndex) phoneticDistance(words, nameWords []string) (sum int, ok bool, numEDs int) {
+	for _, word := range words {
+		best := -1
+		normalizedWord := q.normalize(word)
+		for _, nameWord := range nameWords {
+			if !sharesKey(q.PhoneticEncoder, word, nameWord) {
+				continue
+			}
+			normalizedNameWord := q.normalize(nameWord)
+			numEDs += 1
+			// without a bound, the lengths bound the distance
+			ed := EditDistance(normalizedWord, normalizedNameWord,
+				utf8.RuneCountInString(normalizedWord)+utf8.RuneCountInString(normalizedNameWord))
+			if best < 0 || ed < best {
+				best = ed
+			}
+		}
+		if best < 0 {
+			return
+		}
+		sum += best
+	}
+	ok = true
+	return
+}
+
+// phoneticCandidates returns the ids of the entities that share a key with
+// every word, in ascending order.
+func (q *QGramIndex) phoneticCandidates(words []string) (entityIds []int) {
+	if len(words) == 0 {
+		return
+	}
+
+	counts := make(map[int]int)
+	for _, word := range words {
+		seen := make(map[int]bool)
+		for _, key := range q.PhoneticEncoder.Keys(word) {
+			for _, entityId := range q.PhoneticLists[key] {
+				if !seen[entityId] {
+					seen[entityId] = true
+					counts[entityId] += 1
+				}
+			}
+		}
+	}
+
+	for entityId, count := range counts {
+		if count == len(words) {
+			entityIds = append(entityIds, entityId)
+		}
+	}
+	sort.Ints(entityIds)
+	return
+}
+
+func sharesKey(e PhoneticEncoder, x, y string) bool {
+	for _, keyX := range e.Keys(x) {
+		for _, keyY := range e.Keys(y) {
+			if keyX == keyY {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoundex(t *testing.T) {
+	tests := []struct {
+		given string
+		want  string
+	}{
+		{"Robert", "R163"},
+		{"Rupert", "R163"},
+		{"Rubin", "R150"},
+		{"Ashcraft", "A261"},
+		{"Tymczak", "T522"},
+		{"Pfister", "P236"},
+		{"Honeyman", "H555"},
+		{"Schwarzenegger", "S625"},
+		{"Shwarzenegger", "S625"},
+		{"Müller", "M460"},
+		{"42", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Soundex(tt.given), tt.given)
+	}
+}
+
+func TestColognePhonetics(t *testing.T) {
+	tests := []struct {
+		given string
+		want  string
+	}{
+		{"Müller-Lüdenscheidt", "65752682"},
+		{"Wikipedia", "3412"},
+		{"Breschnew", "17863"},
+		{"Meyer", "67"},
+		{"Maier", "67"},
+		{"Mayr", "67"},
+		{"Christoph", "47823"},
+		{"Xaver", "4837"},
+		{"Schwarzenegger", "8378647"},
+		{"Shwarzenegger", "8378647"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ColognePhonetics(tt.given), tt.given)
+	}
+}
+
+func TestDoubleMetaphone(t *testing.T) {
+	tests := []struct {
+		given         string
+		wantPrimary   string
+		wantAlternate string
+	}{
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Catherine", "K0RN", "KTRN"},
+		{"Richard", "RXRT", "RKRT"},
+		{"Geoff", "JF", "KF"},
+		{"Maurice", "MRS", "MRS"},
+		{"Aubrey", "APR", "APR"},
+		{"Edge", "AJ", "AJ"},
+		{"Xavier", "SF", "SFR"},
+		{"Schwarzenegger", "XRSN", "XFRT"},
+		{"Shwarzenegger", "XRSN", "XRTS"},
+		{"Çelik", "SLK", "SLK"},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		primary, alternate := DoubleMetaphone(tt.given)
+		assert.Equal(t, tt.wantPrimary, primary, tt.given)
+		assert.Equal(t, tt.wantAlternate, alternate, tt.given)
+	}
+}
+
+func TestParsePhoneticEncoder(t *testing.T) {
+	for _, e := range []PhoneticEncoder{SoundexEncoder{}, DoubleMetaphoneEncoder{}, ColognePhoneticsEncoder{}} {
+		got, err := ParsePhoneticEncoder(e.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, e, got)
+	}
+
+	_, err := ParsePhoneticEncoder("metaphone3")
+	assert.Equal(t, ErrUnknownPhoneticEncoder, err)
+}
+
+func TestQGramIndex_FindMatchesWithPhonetics(t *testing.T) {
+	entities := []Entity{
+		{Name: "Arnold Schwarzenegger", Score: 30},
+		{Name: "Schwarz", Score: 20},
+		{Name: "Shwartz", Score: 5},
+		{Name: "Freiburg", Score: 10},
+	}
+	newIndex := func(e PhoneticEncoder) *QGramIndex {
+		q := NewQGramIndex(3)
+		q.PhoneticEncoder = e
+		for _, entity := range entities {
+			q.AddEntity(entity)
+		}
+		return q
+	}
+
+	tests := []struct {
+		givenEncoder PhoneticEncoder
+		givenX       string
+		givenDelta   int
+		givenMode    MatchMode
+		wantNames    []string
+		wantPEDs     []int
+		wantPhonetic int
+	}{
+		// the prefix of the full name is far from the query, the word is close
+		// Shwartz shares the alternate code XRTS and ranks last
+		{DoubleMetaphoneEncoder{}, "Shwarzenegger", 1, PrefixMatch,
+			[]string{"Arnold Schwarzenegger", "Shwartz"}, []int{1, 8}, 2},
+		{ColognePhoneticsEncoder{}, "Shwarzenegger", 1, PrefixMatch,
+			[]string{"Arnold Schwarzenegger"}, []int{1}, 1},
+		// every word must sound like a word of the name
+		{SoundexEncoder{}, "arnold shwarzenegger", 0, PrefixMatch,
+			[]string{"Arnold Schwarzenegger"}, []int{1}, 1},
+		{SoundexEncoder{}, "arnie shwarzenegger", 0, PrefixMatch,
+			nil, nil, 0},
+		// found both ways, the smaller distance wins
+		{ColognePhoneticsEncoder{}, "schwarz", 1, LevenshteinMatch,
+			[]string{"Schwarz", "Shwartz"}, []int{0, 2}, 1},
+		{nil, "schwarz", 1, LevenshteinMatch,
+			[]string{"Schwarz"}, []int{0}, 0},
+	}
+
+	for _, tt := range tests {
+		matches, _, numPhonetic := newIndex(tt.givenEncoder).FindMatchesWithPhonetics(tt.givenX, tt.givenDelta, tt.givenMode)
+		var names []string
+		var peds []int
+		for _, match := range RankMatches(matches) {
+			names = append(names, match.Entity.Name)
+			peds = append(peds, match.PED)
+		}
+		assert.Equal(t, tt.wantNames, names, tt.givenX)
+		assert.Equal(t, tt.wantPEDs, peds, tt.givenX)
+		assert.Equal(t, tt.wantPhonetic, numPhonetic, tt.givenX)
+	}
+}
@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Entity struct {
@@ -23,6 +24,26 @@ type QGramIndex struct {
 	Padding       string
 	InvertedLists map[string][]int
 	EntityMap     map[int]Entity
+	// AliasMap maps the ids of the aliases to their entities, see AddAlias.
+	// Names and aliases share one id space, the inverted lists contain both.
+	AliasMap map[int]Alias
+	// FoldAccents folds accented letters to ASCII before computing q-grams
+	// and edit distances, e.g. "Amélie" to "amelie", see FoldAccents.
+	FoldAccents bool
+	// PhoneticEncoder, if set, must be set before adding entities, which are
+	// then also added to PhoneticLists under the keys of their words, see
+	// FindMatchesWithPhonetics.
+	PhoneticEncoder PhoneticEncoder
+	PhoneticLists   map[string][]int
+
+	// aliasIds maps an entity id to the ids of its aliases
+	aliasIds map[int][]int
+}
+
+// Alias is another name of an entity, e.g. "NYC" of "New York City".
+type Alias struct {
+	EntityId int
+	Name     string
 }
 
 // NewQGramIndex creates an empty QGramIndex.
@@ -32,6 +53,9 @@ func NewQGramIndex(q int) *QGramIndex {
 		Padding:       strings.Repeat("$", q-1),
 		InvertedLists: make(map[string][]int),
 		EntityMap:     make(map[int]Entity),
+		AliasMap:      make(map[int]Alias),
+		aliasIds:      make(map[int][]int),
+		PhoneticLists: make(map[string][]int),
 	}
 }
 
@@ -42,6 +66,19 @@ func (q *QGramIndex) BuildFromFile(filename string) (err error) {
 		return
 	}
 
+	return ReadEntitiesFromFile(filename, func(entity Entity, aliases []string) {
+		entityId := q.AddEntity(entity)
+		for _, alias := range aliases {
+			q.AddAlias(entityId, alias)
+		}
+	})
+}
+
+// ReadEntitiesFromFile calls add for every entity of the given file, the first
+// line is a header, then one "name\tscore\tdescription" line per entity. The
+// name column may hold aliases after the name, separated by "|", e.g.
+// "New York City|NYC|Big Apple".
+func ReadEntitiesFromFile(filename string, add func(entity Entity, aliases []string)) (err error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return
@@ -57,7 +94,6 @@ func (q *QGramIndex) BuildFromFile(filename string) (err error) {
 	// ignore the first line
 	_ = scanner.Text()
 
-	var wordId int
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.Split(line, "\t")
@@ -66,12 +102,6 @@ func (q *QGramIndex) BuildFromFile(filename string) (err error) {
 			err = errors.New("invalid line format")
 			return
 		}
-		wordId += 1
-
-		var word = parts[0]
-		for _, qgram := range q.ComputeQGram(word) {
-			q.InvertedLists[qgram] = append(q.InvertedLists[qgram], wordId)
-		}
 
 		var score int64
 		score, err = strconv.ParseInt(parts[1], 10, 64)
@@ -79,29 +109,87 @@ func (q *QGramIndex) BuildFromFile(filename string) (err error) {
 			return
 		}
 
-		var description = parts[2]
-		q.EntityMap[wordId] = Entity{
-			Name:        word,
+		names := strings.Split(parts[0], "|")
+		add(Entity{
+			Name:        names[0],
 			Score:       int(score),
-			Description: description,
-		}
+			Description: parts[2],
+		}, names[1:])
 	}
 
 	return
 }
 
+// AddEntity adds the entity to the index and returns its id, ids start at 1.
+func (q *QGramIndex) AddEntity(entity Entity) (wordId int) {
+	wordId = len(q.EntityMap) + len(q.AliasMap) + 1
+	for _, qgram := range q.ComputeQGram(entity.Name) {
+		q.InvertedLists[qgram] = append(q.InvertedLists[qgram], wordId)
+	}
+	q.EntityMap[wordId] = entity
+	if q.PhoneticEncoder != nil {
+		q.addPhoneticKeys(wordId, entity.Name)
+	}
+	return
+}
+
 // ComputeQGram computes q-grams for padded, normalized version of given string.
+// The q-grams consist of q runes, not bytes.
 func (q *QGramIndex) ComputeQGram(word string) (qGramList []string) {
-	word = fmt.Sprintf("%s%s%s", q.Padding, Normalize(word), q.Padding)
-	for i := 0; i < len(word)-q.Q+1; i++ {
-		qGramList = append(qGramList, word[i:i+q.Q])
+	runes := []rune(fmt.Sprintf("%s%s%s", q.Padding, q.normalize(word), q.Padding))
+	for i := 0; i < len(runes)-q.Q+1; i++ {
+		qGramList = append(qGramList, string(runes[i:i+q.Q]))
 	}
 	return
 }
 
+// AddAlias adds another name of the entity to the index and returns its id.
+// Matches of the alias are reported with the entity, see EntityPEDPair.Alias.
+func (q *QGramIndex) AddAlias(entityId int, alias string) (aliasId int) {
+	aliasId = len(q.EntityMap) + len(q.AliasMap) + 1
+	for _, qgram := range q.ComputeQGram(alias) {
+		q.InvertedLists[qgram] = append(q.InvertedLists[qgram], aliasId)
+	}
+	q.AliasMap[aliasId] = Alias{EntityId: entityId, Name: alias}
+	q.aliasIds[entityId] = append(q.aliasIds[entityId], aliasId)
+	if q.PhoneticEncoder != nil {
+		q.addPhoneticKeys(entityId, alias)
+	}
+	return
+}
+
+// Aliases returns the aliases of the entity in the order they were added.
+func (q *QGramIndex) Aliases(entityId int) (aliases []string) {
+	for _, aliasId := range q.aliasIds[entityId] {
+		aliases = append(aliases, q.AliasMap[aliasId].Name)
+	}
+	return
+}
+
+// resolve returns the entity of an id of the inverted lists and the name the
+// id stands for, alias is "" for the name of the entity itself.
+func (q *QGramIndex) resolve(id int) (entityId int, name, alias string) {
+	if a, ok := q.AliasMap[id]; ok {
+		return a.EntityId, a.Name, a.Name
+	}
+	return id, q.EntityMap[id].Name, ""
+}
+
+// normalize normalizes and, if enabled, folds accents.
+func (q *QGramIndex) normalize(raw string) string {
+	if q.FoldAccents {
+		return Normalize(FoldAccents(raw))
+	}
+	return Normalize(raw)
+}
+
 type EntityPEDPair struct {
-	Entity Entity
-	PED    int
+	// EntityId is the id of the entity in the index, 0 if unknown.
+	EntityId int
+	Entity   Entity
+	PED      int
+	// Alias is the alias of the entity that matched, "" if it was its name.
+	Alias string
 }
 
 // Find all entities y with PED(x, y) ≤ δ for the given string x and a given
@@ -114,6 +202,29 @@ type EntityPEDPair struct {
 // PED(x, y) ≤ δ and 'ped' is the actual PED value; (2) 'num_ped_computations'
 // is the number of PED computations done while computing the result.
 func (q *QGramIndex) FindMatches(x string, delta int) (matches []EntityPEDPair, numPEDComputations int) {
+	return q.FindMatchesWithMode(x, delta, PrefixMatch)
+}
+
+// FindMatchesWithMode is FindMatches with the distance selected by mode, the
+// PED of the returned pairs then holds that distance.
+//
+// NOTE: entities without any q-gram in common with x are never candidates, so
+// for a short x and a large δ some matches may be missed.
+func (q *QGramIndex) FindMatchesWithMode(x string, delta int, mode MatchMode) (matches []EntityPEDPair, numPEDComputations int) {
+	numPEDComputations = q.forEachMatch(x, delta, mode, func(entityId int, alias string, ped int) {
+		matches = append(matches, EntityPEDPair{
+			EntityId: entityId,
+			Entity:   q.EntityMap[entityId],
+			PED:      ped,
+			Alias:    alias,
+		})
+	})
+	return
+}
+
+// forEachMatch calls match for every name or alias within distance δ of x, in
+// the order of their ids. An entity may match with several of its names.
+func (q *QGramIndex) forEachMatch(x string, delta int, mode MatchMode, match func(entityId int, alias string, ped int)) (numPEDComputations int) {
 	var lists [][]int
 	for _, qGram := range q.ComputeQGram(x) {
 		if invertedList, ok := q.InvertedLists[qGram]; ok {
@@ -121,54 +232,78 @@ func (q *QGramIndex) FindMatches(x string, delta int) (matches []EntityPEDPair,
 		}
 	}
 
+	normalizedX := q.normalize(x)
+	lenX := utf8.RuneCountInString(normalizedX)
+	distance := mode.distanceTo(normalizedX)
 	for _, yPair := range MergeLists(lists) {
-		yEntity := q.EntityMap[yPair.WordId]
-		y := yEntity.Name
+		entityId, name, alias := q.resolve(yPair.WordId)
+		normalizedY := q.normalize(name)
 
-		// NOTE: special case, if delta == 0, x must be prefix of y
-		if delta == 0 && !strings.HasPrefix(y, x) {
-			continue
+		// NOTE: special case, if delta == 0, x must be prefix of y, or y itself
+		if delta == 0 {
+			if mode == PrefixMatch && !strings.HasPrefix(normalizedY, normalizedX) {
+				continue
+			}
+			if mode != PrefixMatch && normalizedY != normalizedX {
+				continue
+			}
 		}
 
-		if yPair.Count < len(x)-1-delta*q.Q {
+		lenY := utf8.RuneCountInString(normalizedY)
+		if yPair.Count < mode.minCommonQGrams(q.Q, lenX, lenY, delta) {
 			continue
 		}
 
 		numPEDComputations += 1
-		if ped := PrefixEditDistance(Normalize(x), Normalize(y), delta); ped <= delta {
-			matches = append(matches, EntityPEDPair{
-				Entity: yEntity,
-				PED:    ped,
-			})
+		if ped := distance(normalizedY, delta); ped <= delta {
+			match(entityId, alias, ped)
 		}
 	}
 
 	return
 }
 
+// RankMatches sorts the matches by PED, then by score. Several matches of the
+// same entity, e.g. by different aliases, are collapsed to the best one, the
+// name of the entity itself wins a tie. Matches are of the same entity if they
+// have the same EntityId, matches without one are never collapsed.
 func RankMatches(matches []EntityPEDPair) (sorted []EntityPEDPair) {
 	sorted = make([]EntityPEDPair, len(matches))
 	copy(sorted, matches)
 	sort.Slice(sorted, func(i, j int) bool {
-		mi, mj := sorted[i], sorted[j]
-		if mi.PED != mj.PED {
-			return mi.PED < mj.PED
-		}
-
-		return mi.Entity.Score > mj.Entity.Score
+		return rankedBefore(sorted[i], sorted[j])
 	})
+
+	seen := make(map[int]bool, len(sorted))
+	collapsed := sorted[:0]
+	for _, match := range sorted {
+		if match.EntityId != 0 {
+			if seen[match.EntityId] {
+				continue
+			}
+			seen[match.EntityId] = true
+		}
+		collapsed = append(collapsed, match)
+	}
+	sorted = collapsed
 	return
 }
 
-var nonWordCharacterRegexp = regexp.MustCompile(`\W`)
-
 /**
  * Normalize the given string (remove non-word characters and lower case). In
  * the lecture, this was part of the qGrams method, but we also need it as a
  * separate method when computing the EDs for the remaining candidates.
+ *
+ * Word characters are Unicode letters, digits and '_', so "Fürstin" keeps its
+ * "ü", unlike with the regexp \W which only knows ASCII.
  */
 func Normalize(raw string) string {
-	return nonWordCharacterRegexp.ReplaceAllString(strings.ToLower(raw), "")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, raw)
 }
 
 type WordIdCountPair struct {
@@ -228,8 +363,12 @@ func MergeLists(lists [][]int) (ret []WordIdCountPair) {
 //
 // NOTE: The method must run in time O(|x| * (|x| + δ)), as explained in the
 // lecture.
+//
+// NOTE: the distance is computed on runes, not bytes.
 //noinspection GoNilness
-func PrefixEditDistance(x, y string, delta int) (ped int) {
+func PrefixEditDistance(xStr, yStr string, delta int) (ped int) {
+	x, y := []rune(xStr), []rune(yStr)
+
 	// NOTE: ped = 0 for empty word
 	if len(x) == 0 {
 		return
@@ -68,6 +68,10 @@ func TestQGramIndex_ComputeQGram(t *testing.T) {
 				"ibu", "bur", "urg", "rg$", "g$$",
 			},
 		},
+		{
+			3, "Fürth",
+			[]string{"$$f", "$fü", "für", "ürt", "rth", "th$", "h$$"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -89,8 +93,9 @@ func TestQGramIndex_FindMatches(t *testing.T) {
 			3, "example.tsv", "frei", 0,
 			[]EntityPEDPair{
 				{
-					Entity: Entity{"frei", 3, "a word"},
-					PED:    0,
+					EntityId: 1,
+					Entity:   Entity{"frei", 3, "a word"},
+					PED:      0,
 				},
 			},
 			1,
@@ -99,12 +104,14 @@ func TestQGramIndex_FindMatches(t *testing.T) {
 			3, "example.tsv", "frei", 2,
 			[]EntityPEDPair{
 				{
-					Entity: Entity{"frei", 3, "a word"},
-					PED:    0,
+					EntityId: 1,
+					Entity:   Entity{"frei", 3, "a word"},
+					PED:      0,
 				},
 				{
-					Entity: Entity{"brei", 2, "another word"},
-					PED:    1,
+					EntityId: 2,
+					Entity:   Entity{"brei", 2, "another word"},
+					PED:      1,
 				},
 			},
 			2,
@@ -113,8 +120,9 @@ func TestQGramIndex_FindMatches(t *testing.T) {
 			3, "example.tsv", "freibu", 2,
 			[]EntityPEDPair{
 				{
-					Entity: Entity{"frei", 3, "a word"},
-					PED:    2,
+					EntityId: 1,
+					Entity:   Entity{"frei", 3, "a word"},
+					PED:      2,
 				},
 			},
 			2,
@@ -151,6 +159,30 @@ func TestRankMatches(t *testing.T) {
 				{Entity: Entity{"foo", 3, "word 0"}, PED: 2},
 			},
 		},
+		// matches of the same entity are collapsed to the best one
+		{
+			[]EntityPEDPair{
+				{EntityId: 1, Entity: Entity{"foo", 3, "word 0"}, PED: 2},
+				{EntityId: 1, Entity: Entity{"foo", 3, "word 0"}, PED: 1, Alias: "fooo"},
+				{EntityId: 2, Entity: Entity{"bar", 7, "word 1"}, PED: 1, Alias: "baz"},
+				{EntityId: 2, Entity: Entity{"bar", 7, "word 1"}, PED: 1},
+			},
+			[]EntityPEDPair{
+				{EntityId: 2, Entity: Entity{"bar", 7, "word 1"}, PED: 1},
+				{EntityId: 1, Entity: Entity{"foo", 3, "word 0"}, PED: 1, Alias: "fooo"},
+			},
+		},
+		// different entities with equal fields are not collapsed
+		{
+			[]EntityPEDPair{
+				{EntityId: 1, Entity: Entity{"X", 0, "d"}, PED: 0},
+				{EntityId: 2, Entity: Entity{"X", 0, "d"}, PED: 0},
+			},
+			[]EntityPEDPair{
+				{EntityId: 1, Entity: Entity{"X", 0, "d"}, PED: 0},
+				{EntityId: 2, Entity: Entity{"X", 0, "d"}, PED: 0},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,6 +197,8 @@ func TestNormalize(t *testing.T) {
 	}{
 		{"Frei, burg !!", "freiburg"},
 		{"freiburg", "freiburg"},
+		{"Amélie Poulain", "améliepoulain"},
+		{"Москва!", "москва"},
 	}
 
 	for _, tt := range tests {
@@ -208,9 +242,60 @@ func TestPrefixEditDistance(t *testing.T) {
 		{"frei", "freiburg", 0, 0},
 		{"frei", "breifurg", 1, 1},
 		{"freiburg", "stuttgart", 2, 3},
+		{"amelie", "amélie", 1, 1},
+		{"fürs", "fürstin", 0, 0},
+		{"москва", "моск", 2, 2},
 	}
 
 	for _, tt := range tests {
 		assert.Equal(t, tt.wantPED, PrefixEditDistance(tt.givenX, tt.givenY, tt.givenDelta))
 	}
 }
+
+func TestFoldAccents(t *testing.T) {
+	tests := []struct {
+		givenStr string
+		wantStr  string
+	}{
+		{"Amélie", "Amelie"},
+		{"Fürstin", "Fuerstin"},
+		{"Straße", "Strasse"},
+		{"Dvořák", "Dvorak"},
+		{"Москва", "Москва"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantStr, FoldAccents(tt.givenStr))
+	}
+}
+
+func TestQGramIndex_FindMatches_Unicode(t *testing.T) {
+	tests := []struct {
+		givenFoldAccents bool
+		givenX           string
+		givenDelta       int
+		wantNames        []string
+	}{
+		{false, "amélie", 0, []string{"Amélie"}},
+		{false, "amelie", 1, []string{"Amélie"}},
+		{false, "fürs", 0, []string{"Fürstin"}},
+		{false, "fuerstin", 1, nil},
+		{true, "amelie", 0, []string{"Amélie"}},
+		{true, "Fuerstin", 0, []string{"Fürstin"}},
+		{true, "fursti", 1, []string{"Fürstin"}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			q := NewQGramIndex(3)
+			q.FoldAccents = tt.givenFoldAccents
+			assert.NoError(t, q.BuildFromFile("example_unicode.tsv"))
+			matches, _ := q.FindMatches(tt.givenX, tt.givenDelta)
+			var names []string
+			for _, match := range RankMatches(matches) {
+				names = append(names, match.Entity.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package index
+
+// soundexCodes maps the consonants to their Soundex digits, vowels, h, w and y
+// have no code.
+var soundexCodes = map[rune]byte{
+	'B': '1', 'F': '1', 'P': '1', 'V': '1',
+	'C': '2', 'G': '2', 'J': '2', 'K': '2', 'Q': '2', 'S': '2', 'X': '2', 'Z': '2',
+	'D': '3', 'T': '3',
+	'L': '4',
+	'M': '5', 'N': '5',
+	'R': '6',
+}
+
+// Soundex returns the American Soundex code of the word, its first letter and
+// three digits, e.g. "Robert" and "Rupert" to "R163". Adjacent letters with
+// the same digit are coded once, also when separated by h or w but not when
+// separated by a vowel. It returns "" for a word without ASCII letters.
+func Soundex(word string) string {
+	letters := phoneticLetters(word)
+	if len(letters) == 0 {
+		return ""
+	}
+
+	code := []byte{byte(letters[0])}
+	last := soundexCodes[letters[0]]
+	for _, r := range letters[1:] {
+		if len(code) == 4 {
+			break
+		}
+		digit := soundexCodes[r]
+		if digit != 0 && digit != last {
+			code = append(code, digit)
+		}
+		if r != 'H' && r != 'W' {
+			last = digit
+		}
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
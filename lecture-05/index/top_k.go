@@ -0,0 +1,183 @@
+package index
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// rankedBefore reports whether a is ranked before b, see RankMatches.
+func rankedBefore(a, b EntityPEDPair) bool {
+	if a.PED != b.PED {
+		return a.PED < b.PED
+	}
+	if a.Entity.Score != b.Entity.Score {
+		return a.Entity.Score > b.Entity.Score
+	}
+	return a.Alias == "" && b.Alias != ""
+}
+
+// matchHeap keeps the worst ranked match on top.
+type matchHeap []EntityPEDPair
+
+func (h matchHeap) Len() int            { return len(h) }
+func (h matchHeap) Less(i, j int) bool  { return rankedBefore(h[j], h[i]) }
+func (h matchHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *matchHeap) Push(x interface{}) { *h = append(*h, x.(EntityPEDPair)) }
+func (h matchHeap) indexOf(entityId int) int {
+	for i, match := range h {
+		if match.EntityId == entityId {
+			return i
+		}
+	}
+	return -1
+}
+
+func (h *matchHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// minDistance returns the smallest distance x and y can have with count
+// common q-grams, or δ + 1 if the count filter rules out every distance ≤ δ.
+func (m MatchMode) minDistance(q, lenX, lenY, count, delta int) int {
+	p := 0
+	if m != PrefixMatch {
+		p = absInt(lenX - lenY)
+	}
+	for ; p <= delta; p++ {
+		if count >= m.minCommonQGrams(q, lenX, lenY, p) {
+			break
+		}
+	}
+	return p
+}
+
+type topKCandidate struct {
+	entityId    int
+	name, alias string
+	minDistance int
+}
+
+// FindTopKMatches returns the k best ranked matches of FindMatchesWithMode,
+// ranked like RankMatches, without computing the distance of every candidate.
+//
+// The candidates are processed by their lower bound on the distance derived
+// from the number of common q-grams, then by their score. Once k matches are
+// found, the worst of them is the threshold: the remaining candidates are only
+// compared up to its distance, and the search stops as soon as no remaining
+// candidate can be ranked before it. numPEDComputationsSaved is the number of
+// candidates FindMatchesWithMode would have computed the distance for, but
+// FindTopKMatches did not. An entity matching with several aliases is
+// returned once, like by RankMatches.
+func (q *QGramIndex) FindTopKMatches(x string, delta, k int, mode MatchMode) (matches []EntityPEDPair, numPEDComputations, numPEDComputationsSaved int) {
+	if k <= 0 {
+		return
+	}
+
+	var lists [][]int
+	for _, qGram := range q.ComputeQGram(x) {
+		if invertedList, ok := q.InvertedLists[qGram]; ok {
+			lists = append(lists, invertedList)
+		}
+	}
+
+	normalizedX := q.normalize(x)
+	lenX := utf8.RuneCountInString(normalizedX)
+	var candidates []topKCandidate
+	for _, yPair := range MergeLists(lists) {
+		entityId, name, alias := q.resolve(yPair.WordId)
+		normalizedY := q.normalize(name)
+		lenY := utf8.RuneCountInString(normalizedY)
+
+		// NOTE: the same filters as in FindMatchesWithMode
+		if delta == 0 {
+			if mode == PrefixMatch && !strings.HasPrefix(normalizedY, normalizedX) {
+				continue
+			}
+			if mode != PrefixMatch && normalizedY != normalizedX {
+				continue
+			}
+		}
+		if yPair.Count < mode.minCommonQGrams(q.Q, lenX, lenY, delta) {
+			continue
+		}
+
+		candidates = append(candidates, topKCandidate{
+			entityId:    entityId,
+			name:        name,
+			alias:       alias,
+			minDistance: mode.minDistance(q.Q, lenX, lenY, yPair.Count, delta),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.minDistance != cj.minDistance {
+			return ci.minDistance < cj.minDistance
+		}
+		si, sj := q.EntityMap[ci.entityId].Score, q.EntityMap[cj.entityId].Score
+		if si != sj {
+			return si > sj
+		}
+		if ci.entityId != cj.entityId {
+			return ci.entityId < cj.entityId
+		}
+		return ci.alias == "" && cj.alias != ""
+	})
+
+	distance := mode.distanceTo(normalizedX)
+	h := &matchHeap{}
+	for _, c := range candidates {
+		if c.minDistance > delta {
+			break
+		}
+
+		entity := q.EntityMap[c.entityId]
+		threshold := delta
+		if h.Len() == k {
+			worst := (*h)[0]
+			// all remaining candidates have a larger lower bound, or the same
+			// one and a score that is not higher
+			if !rankedBefore(EntityPEDPair{Entity: entity, PED: c.minDistance}, worst) {
+				break
+			}
+			if worst.PED < threshold {
+				threshold = worst.PED
+			}
+		}
+
+		numPEDComputations += 1
+		match := EntityPEDPair{
+			EntityId: c.entityId,
+			Entity:   entity,
+			PED:      distance(q.normalize(c.name), threshold),
+			Alias:    c.alias,
+		}
+		if match.PED > threshold {
+			continue
+		}
+		// another alias of the entity may be in the top k already
+		if i := h.indexOf(c.entityId); i >= 0 {
+			if rankedBefore(match, (*h)[i]) {
+				(*h)[i] = match
+				heap.Fix(h, i)
+			}
+		} else if h.Len() < k {
+			heap.Push(h, match)
+		} else if rankedBefore(match, (*h)[0]) {
+			(*h)[0] = match
+			heap.Fix(h, 0)
+		}
+	}
+	numPEDComputationsSaved = len(candidates) - numPEDComputations
+
+	matches = make([]EntityPEDPair, h.Len())
+	for i := len(matches) - 1; i >= 0; i-- {
+		matches[i] = heap.Pop(h).(EntityPEDPair)
+	}
+	return
+}
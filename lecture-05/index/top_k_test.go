@@ -0,0 +1,97 @@
+package index
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQGramIndex_FindTopKMatches(t *testing.T) {
+	tests := []struct {
+		givenX                      string
+		givenDelta                  int
+		givenK                      int
+		wantMatches                 []EntityPEDPair
+		wantNumPEDComputations      int
+		wantNumPEDComputationsSaved int
+	}{
+		{
+			"frei", 2, 1,
+			[]EntityPEDPair{{EntityId: 1, Entity: Entity{"frei", 3, "a word"}, PED: 0}},
+			1, 1,
+		},
+		{
+			"frei", 2, 5,
+			[]EntityPEDPair{
+				{EntityId: 1, Entity: Entity{"frei", 3, "a word"}, PED: 0},
+				{EntityId: 2, Entity: Entity{"brei", 2, "another word"}, PED: 1},
+			},
+			2, 0,
+		},
+		{"frei", 2, 0, nil, 0, 0},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			q := NewQGramIndex(3)
+			assert.NoError(t, q.BuildFromFile("example.tsv"))
+			matches, numPEDComputations, numPEDComputationsSaved := q.FindTopKMatches(tt.givenX, tt.givenDelta, tt.givenK, PrefixMatch)
+			assert.Equal(t, tt.wantMatches, matches, "matches")
+			assert.Equal(t, tt.wantNumPEDComputations, numPEDComputations, "numPEDComputations")
+			assert.Equal(t, tt.wantNumPEDComputationsSaved, numPEDComputationsSaved, "numPEDComputationsSaved")
+		})
+	}
+}
+
+// FindTopKMatches must return the same ranking as ranking all matches, up to
+// the order of matches with the same PED and score.
+func TestQGramIndex_FindTopKMatches_SameAsRankMatches(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	randomWord := func() string {
+		b := make([]byte, 2+r.Intn(8))
+		for i := range b {
+			b[i] = "abcd"[r.Intn(4)]
+		}
+		return string(b)
+	}
+
+	dir, err := ioutil.TempDir("", "qgram")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "entities.tsv")
+	content := "name\tscore\tdescription\n"
+	for i := 0; i < 1000; i++ {
+		content += fmt.Sprintf("%s\t%d\tword %d\n", randomWord(), r.Intn(50), i)
+	}
+	assert.NoError(t, ioutil.WriteFile(filename, []byte(content), 0644))
+
+	qi := NewQGramIndex(3)
+	assert.NoError(t, qi.BuildFromFile(filename))
+	for _, mode := range []MatchMode{PrefixMatch, LevenshteinMatch, DamerauMatch} {
+		for i := 0; i < 100; i++ {
+			x, delta, k := randomWord(), r.Intn(3), 1+r.Intn(10)
+
+			all, numAll := qi.FindMatchesWithMode(x, delta, mode)
+			want := RankMatches(all)
+			if len(want) > k {
+				want = want[:k]
+			}
+
+			got, numPEDComputations, numPEDComputationsSaved := qi.FindTopKMatches(x, delta, k, mode)
+			assert.Equal(t, rankKeys(want), rankKeys(got), "mode=%s x=%s delta=%d k=%d", mode, x, delta, k)
+			assert.Equal(t, numAll, numPEDComputations+numPEDComputationsSaved)
+		}
+	}
+}
+
+func rankKeys(matches []EntityPEDPair) (keys []string) {
+	for _, match := range matches {
+		keys = append(keys, fmt.Sprintf("%d/%d", match.PED, match.Entity.Score))
+	}
+	return
+}
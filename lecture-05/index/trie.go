@@ -0,0 +1,222 @@
+package index
+
+import (
+	"errors"
+	"sort"
+)
+
+// Matcher finds the entities within distance δ of a query, QGramIndex and
+// TrieIndex are two implementations. The second return value counts the work
+// done: PED computations for the q-gram index, visited trie nodes for the trie.
+type Matcher interface {
+	FindMatchesWithMode(x string, delta int, mode MatchMode) ([]EntityPEDPair, int)
+}
+
+// TrieIndex stores the normalized entity names in a trie whose children are
+// sorted by rune. FindMatchesWithMode intersects the trie with the Levenshtein
+// automaton of the query: a depth-first search carries the DP row of the query
+// against the path to each node, which is the state of the automaton, and
+// leaves a subtree as soon as the row shows that no name below can match.
+type TrieIndex struct {
+	EntityMap map[int]Entity
+	// AliasMap maps the ids of the aliases to their entities, see QGramIndex.
+	AliasMap map[int]Alias
+	// FoldAccents folds accented letters to ASCII, see QGramIndex.
+	FoldAccents bool
+	NumNodes    int
+
+	root *trieNode
+}
+
+type trieNode struct {
+	label    rune
+	children []*trieNode
+	// ids of the names and aliases ending here
+	ids []int
+}
+
+func NewTrieIndex() *TrieIndex {
+	return &TrieIndex{
+		EntityMap: make(map[int]Entity),
+		AliasMap:  make(map[int]Alias),
+		NumNodes:  1,
+		root:      &trieNode{},
+	}
+}
+
+// BuildFromFile builds index from given file, see ReadEntitiesFromFile.
+func (t *TrieIndex) BuildFromFile(filename string) (err error) {
+	if t == nil {
+		err = errors.New("got nil TrieIndex")
+		return
+	}
+
+	return ReadEntitiesFromFile(filename, func(entity Entity, aliases []string) {
+		entityId := t.AddEntity(entity)
+		for _, alias := range aliases {
+			t.AddAlias(entityId, alias)
+		}
+	})
+}
+
+// AddEntity adds the entity to the index and returns its id, ids start at 1.
+func (t *TrieIndex) AddEntity(entity Entity) (entityId int) {
+	entityId = len(t.EntityMap) + len(t.AliasMap) + 1
+	t.EntityMap[entityId] = entity
+	t.insert(entity.Name, entityId)
+	return
+}
+
+// AddAlias adds another name of the entity and returns its id, see
+// QGramIndex.AddAlias.
+func (t *TrieIndex) AddAlias(entityId int, alias string) (aliasId int) {
+	aliasId = len(t.EntityMap) + len(t.AliasMap) + 1
+	t.AliasMap[aliasId] = Alias{EntityId: entityId, Name: alias}
+	t.insert(alias, aliasId)
+	return
+}
+
+func (t *TrieIndex) insert(name string, id int) {
+	node := t.root
+	for _, r := range t.normalize(name) {
+		i := sort.Search(len(node.children), func(i int) bool {
+			return node.children[i].label >= r
+		})
+		if i == len(node.children) || node.children[i].label != r {
+			node.children = append(node.children, nil)
+			copy(node.children[i+1:], node.children[i:])
+			node.children[i] = &trieNode{label: r}
+			t.NumNodes += 1
+		}
+		node = node.children[i]
+	}
+	node.ids = append(node.ids, id)
+}
+
+func (t *TrieIndex) normalize(raw string) string {
+	if t.FoldAccents {
+		return Normalize(FoldAccents(raw))
+	}
+	return Normalize(raw)
+}
+
+// FindMatches finds all entities y with PED(x, y) ≤ δ, like
+// QGramIndex.FindMatches, and returns the number of visited trie nodes.
+func (t *TrieIndex) FindMatches(x string, delta int) (matches []EntityPEDPair, numVisitedNodes int) {
+	return t.FindMatchesWithMode(x, delta, PrefixMatch)
+}
+
+// FindMatchesWithMode is FindMatches with the distance selected by mode. The
+// matches are in the order of the names in the trie.
+func (t *TrieIndex) FindMatchesWithMode(x string, delta int, mode MatchMode) (matches []EntityPEDPair, numVisitedNodes int) {
+	s := &trieSearch{
+		t:     t,
+		x:     []rune(t.normalize(x)),
+		delta: delta,
+		mode:  mode,
+	}
+
+	// the row of the empty prefix of y
+	row := make([]int, len(s.x)+1)
+	for i := range row {
+		row[i] = i
+	}
+	best := len(s.x)
+	s.collect(t.root, best)
+	for _, child := range t.root.children {
+		s.visit(child, nil, row, 0, best)
+	}
+	return s.matches, s.numVisitedNodes
+}
+
+type trieSearch struct {
+	t               *TrieIndex
+	x               []rune
+	delta           int
+	mode            MatchMode
+	matches         []EntityPEDPair
+	numVisitedNodes int
+}
+
+// visit computes the row of node from the rows of its parent and grandparent,
+// parentLabel is the label of the parent. For the prefix edit distance, best is
+// the smallest last cell of all rows on the path, i.e. the PED of x to the
+// path so far.
+func (s *trieSearch) visit(node *trieNode, prevPrev, prev []int, parentLabel rune, best int) {
+	s.numVisitedNodes += 1
+	m := len(s.x)
+
+	row := make([]int, m+1)
+	row[0] = prev[0] + 1
+	rowMin := row[0]
+	for i := 1; i <= m; i++ {
+		cost := 1
+		if s.x[i-1] == node.label {
+			cost = 0
+		}
+		d := minInt(prev[i-1]+cost, minInt(prev[i]+1, row[i-1]+1))
+		if s.mode == DamerauMatch && prevPrev != nil && i > 1 &&
+			s.x[i-1] == parentLabel && s.x[i-2] == node.label {
+			d = minInt(d, prevPrev[i-2]+1)
+		}
+		row[i] = d
+		rowMin = minInt(rowMin, d)
+	}
+
+	if s.mode == PrefixMatch {
+		best = minInt(best, row[m])
+		// no cell of the row is below best, so all names below have PED best
+		if best <= s.delta && rowMin >= best {
+			s.collectSubtree(node, best)
+			return
+		}
+		s.collect(node, best)
+		if rowMin > s.delta {
+			return
+		}
+	} else {
+		s.collect(node, row[m])
+		// a transposition may still use the previous row
+		prevMin := rowMin
+		if s.mode == DamerauMatch {
+			prevMin = minSlice(prev) + 1
+		}
+		if rowMin > s.delta && prevMin > s.delta {
+			return
+		}
+	}
+
+	for _, child := range node.children {
+		s.visit(child, prev, row, node.label, best)
+	}
+}
+
+// collect adds the entities of the node if their distance is at most δ.
+func (s *trieSearch) collect(node *trieNode, distance int) {
+	if distance > s.delta {
+		return
+	}
+	for _, id := range node.ids {
+		match := EntityPEDPair{EntityId: id, PED: distance}
+		if alias, ok := s.t.AliasMap[id]; ok {
+			match.EntityId, match.Alias = alias.EntityId, alias.Name
+		}
+		match.Entity = s.t.EntityMap[match.EntityId]
+		s.matches = append(s.matches, match)
+	}
+}
+
+func (s *trieSearch) collectSubtree(node *trieNode, distance int) {
+	s.collect(node, distance)
+	for _, child := range node.children {
+		s.collectSubtree(child, distance)
+	}
+}
+
+func minSlice(values []int) int {
+	ret := values[0]
+	for _, v := range values[1:] {
+		ret = minInt(ret, v)
+	}
+	return ret
+}
@@ -0,0 +1,96 @@
+package index
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrieIndex_BuildFromFile(t *testing.T) {
+	ti := NewTrieIndex()
+	assert.NoError(t, ti.BuildFromFile("example.tsv"))
+	assert.Len(t, ti.EntityMap, 2)
+	// root, b-r-e-i and f-r-e-i
+	assert.Equal(t, 9, ti.NumNodes)
+	assert.Equal(t, []rune{'b', 'f'}, []rune{ti.root.children[0].label, ti.root.children[1].label})
+}
+
+func TestTrieIndex_FindMatches(t *testing.T) {
+	tests := []struct {
+		givenX      string
+		givenDelta  int
+		wantMatches []EntityPEDPair
+	}{
+		{
+			"frei", 0,
+			[]EntityPEDPair{{EntityId: 1, Entity: Entity{"frei", 3, "a word"}, PED: 0}},
+		},
+		{
+			"frei", 2,
+			[]EntityPEDPair{
+				{EntityId: 2, Entity: Entity{"brei", 2, "another word"}, PED: 1},
+				{EntityId: 1, Entity: Entity{"frei", 3, "a word"}, PED: 0},
+			},
+		},
+		{
+			"freibu", 2,
+			[]EntityPEDPair{{EntityId: 1, Entity: Entity{"frei", 3, "a word"}, PED: 2}},
+		},
+		{"stuttgart", 2, nil},
+	}
+
+	ti := NewTrieIndex()
+	assert.NoError(t, ti.BuildFromFile("example.tsv"))
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			matches, _ := ti.FindMatches(tt.givenX, tt.givenDelta)
+			assert.Equal(t, tt.wantMatches, matches)
+		})
+	}
+}
+
+// The trie is exact, so it must find the same entities as computing the
+// distance to every entity, unlike the q-gram index it also finds entities
+// without a common q-gram.
+func TestTrieIndex_FindMatchesWithMode_Differential(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	alphabet := []rune("abcü")
+	randomWord := func() string {
+		w := make([]rune, r.Intn(9))
+		for i := range w {
+			w[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(w)
+	}
+
+	ti := NewTrieIndex()
+	for i := 0; i < 500; i++ {
+		ti.AddEntity(Entity{Name: randomWord(), Score: i})
+	}
+
+	for _, mode := range []MatchMode{PrefixMatch, LevenshteinMatch, DamerauMatch} {
+		for i := 0; i < 200; i++ {
+			x, delta := randomWord(), r.Intn(4)
+
+			var want []string
+			for _, entity := range ti.EntityMap {
+				if d := mode.distance(x, entity.Name, delta); d <= delta {
+					want = append(want, fmt.Sprintf("%d/%d", entity.Score, d))
+				}
+			}
+			sort.Strings(want)
+
+			matches, _ := ti.FindMatchesWithMode(x, delta, mode)
+			var got []string
+			for _, match := range matches {
+				got = append(got, fmt.Sprintf("%d/%d", match.Entity.Score, match.PED))
+			}
+			sort.Strings(got)
+
+			assert.Equal(t, want, got, "mode=%s x=%s delta=%d", mode, x, delta)
+		}
+	}
+}
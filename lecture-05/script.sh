@@ -4,4 +4,45 @@
 sh download.sh
 
 # demo
-go run cmd/demo/main.go ../data/wikidata-entities.tsv
\ No newline at end of file
+go run cmd/demo/main.go ../data/wikidata-entities.tsv
+# demo, folding accents (é -> e, ü -> ue) before indexing
+go run cmd/demo/main.go -fold-accents ../data/wikidata-entities.tsv
+
+# demo, spelling correction of complete words, a swap of adjacent characters
+# counts as one edit
+go run cmd/demo/main.go -mode damerau ../data/wikidata-entities.tsv
+
+# demo, multi-word queries: every keyword is matched against the words of the
+# entity names, the last one as a prefix
+go run cmd/demo/main.go -multi-word ../data/wikidata-entities.tsv
+
+# demo, top 5 matches only, stops once no candidate can enter the top 5
+go run cmd/demo/main.go -top-k ../data/wikidata-entities.tsv
+
+# batch mode, regenerates the result table of the README as TSV (time in ms,
+# #PED, #RES and the top 5)
+go run cmd/demo/main.go -q 3 -delta len/4 -k 5 -queries queries.txt ../data/wikidata-entities.tsv
+
+# demo, candidates from a Levenshtein automaton over a trie of the names instead
+# of the q-gram index
+go run cmd/demo/main.go -index trie ../data/wikidata-entities.tsv
+
+# q-gram index vs trie on 100000 generated names, candidates are PED
+# computations and visited trie nodes
+go test -run xxx -bench FindMatches ./index/
+
+# demo, also returns the entities whose words sound like the keywords, e.g.
+# "Shwarzenegger" finds "Arnold Schwarzenegger" at δ = 3
+go run cmd/demo/main.go -phonetic double-metaphone ../data/wikidata-entities.tsv
+# Kölner Phonetik, for German names
+go run cmd/demo/main.go -phonetic cologne ../data/wikidata-entities.tsv
+
+# demo, entities with aliases ("New York City|NYC|Big Apple" in the name column)
+go run cmd/demo/main.go index/example_aliases.tsv
+
+# build the index once and save it in the binary format, later runs load it
+go run cmd/demo/main.go -save wikidata-entities.qgram ../data/wikidata-entities.tsv
+go run cmd/demo/main.go -load wikidata-entities.qgram
+
+# building vs loading 100000 generated names
+go test -run xxx -bench Binary ./index/
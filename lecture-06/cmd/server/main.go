@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-05/index"
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-06/server"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	config := server.DefaultConfig()
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.StringVar(&config.StaticDir, "static", config.StaticDir, "directory of the static files")
+	flag.StringVar(&config.AllowedOrigin, "allowed-origin", config.AllowedOrigin, "allowed CORS origin")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Println("Usage: cmd [-addr :8080] [-static static] [-allowed-origin *] <entities-file>")
+		os.Exit(-1)
+	}
+
+	qi := index.NewQGramIndex(3)
+	if err := qi.BuildFromFile(flag.Arg(0)); err != nil {
+		fmt.Printf("BuildFromFile err %v", err)
+		os.Exit(-1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt)
+	go func() {
+		<-sigc
+		cancel()
+	}()
+
+	fmt.Printf("listening on %s\n", *addr)
+	if err := server.NewServer(qi, config).ListenAndServe(ctx, *addr); err != nil {
+		fmt.Printf("ListenAndServe err %v", err)
+		os.Exit(-1)
+	}
+}
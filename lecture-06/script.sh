@@ -0,0 +1,6 @@
+# autocompletion server for the entities of lecture-05, see lecture-05/script.sh
+# for how to download them, then open http://localhost:8080 and start typing
+go run cmd/server/main.go -static static ../data/wikidata-entities.tsv
+
+# query the JSON endpoint directly
+curl 'http://localhost:8080/complete?q=breib&k=5'
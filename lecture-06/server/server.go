@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-05/index"
+)
+
+type Config struct {
+	// AllowedOrigin is sent as Access-Control-Allow-Origin, "*" allows every
+	// origin.
+	AllowedOrigin string
+	// DefaultK is the number of matches returned without k, MaxK caps k.
+	DefaultK int
+	MaxK     int
+	// StaticDir is served under /, nothing is served if it is empty.
+	StaticDir string
+	// ReadTimeout and WriteTimeout bound reading a request and writing its
+	// response, HandlerTimeout bounds answering it.
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	HandlerTimeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		AllowedOrigin:  "*",
+		DefaultK:       5,
+		MaxK:           100,
+		StaticDir:      "static",
+		ReadTimeout:    5 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		HandlerTimeout: 5 * time.Second,
+	}
+}
+
+// Server answers autocompletion requests with the matches of a q-gram index.
+type Server struct {
+	qi     *index.QGramIndex
+	config Config
+}
+
+func NewServer(qi *index.QGramIndex, config Config) *Server {
+	return &Server{qi: qi, config: config}
+}
+
+type Match struct {
+	Name        string `json:"name"`
+	Score       int    `json:"score"`
+	Description string `json:"description"`
+	PED         int    `json:"ped"`
+	// Alias is the alias that matched, if it was not the name.
+	Alias string `json:"alias,omitempty"`
+}
+
+type CompleteResponse struct {
+	Query              string  `json:"query"`
+	Delta              int     `json:"delta"`
+	Matches            []Match `json:"matches"`
+	NumMatches         int     `json:"numMatches"`
+	NumPEDComputations int     `json:"numPEDComputations"`
+	TimeMillis         float64 `json:"timeMillis"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the handler of /complete and the static files, with CORS
+// headers and HandlerTimeout applied.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/complete", s.handleComplete)
+	if s.config.StaticDir != "" {
+		mux.Handle("/", http.FileServer(http.Dir(s.config.StaticDir)))
+	}
+	return s.cors(s.timeout(mux))
+}
+
+// ListenAndServe serves Handler on addr until ctx is done, then shuts down
+// gracefully.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      s.Handler(),
+		ReadTimeout:  s.config.ReadTimeout,
+		WriteTimeout: s.config.WriteTimeout,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.HandlerTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", s.config.AllowedOrigin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) timeout(next http.Handler) http.Handler {
+	if s.config.HandlerTimeout <= 0 {
+		return next
+	}
+	return http.TimeoutHandler(next, s.config.HandlerTimeout, `{"error":"request timed out"}`)
+}
+
+// handleComplete answers GET /complete?q=<query>&k=<number of matches>.
+func (s *Server) handleComplete(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{"only GET is allowed"})
+		return
+	}
+
+	k := s.config.DefaultK
+	if raw := r.URL.Query().Get("k"); raw != "" {
+		var err error
+		if k, err = strconv.Atoi(raw); err != nil || k <= 0 {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{"k must be a positive integer"})
+			return
+		}
+	}
+	if k > s.config.MaxK {
+		k = s.config.MaxK
+	}
+
+	x := r.URL.Query().Get("q")
+	resp := CompleteResponse{
+		Query:   x,
+		Delta:   utf8.RuneCountInString(index.Normalize(x)) / 4,
+		Matches: []Match{},
+	}
+	if index.Normalize(x) != "" {
+		matches, numPEDComputations := s.qi.FindMatches(x, resp.Delta)
+		// the matches of an entity by its aliases are collapsed, count it once
+		matches = index.RankMatches(matches)
+		for i, match := range matches {
+			if i >= k {
+				break
+			}
+			resp.Matches = append(resp.Matches, Match{
+				Name:        match.Entity.Name,
+				Score:       match.Entity.Score,
+				Description: match.Entity.Description,
+				PED:         match.PED,
+				Alias:       match.Alias,
+			})
+		}
+		resp.NumMatches = len(matches)
+		resp.NumPEDComputations = numPEDComputations
+	}
+	resp.TimeMillis = float64(time.Since(startTime).Microseconds()) / 1000
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
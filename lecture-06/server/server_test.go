@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ZhengHe-MD/ir-freiburg.git/lecture-05/index"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return newTestServerFromFile(t, "../../lecture-05/index/example.tsv")
+}
+
+func newTestServerFromFile(t *testing.T, filename string) *httptest.Server {
+	qi := index.NewQGramIndex(3)
+	assert.NoError(t, qi.BuildFromFile(filename))
+	config := DefaultConfig()
+	config.StaticDir = "../static"
+	return httptest.NewServer(NewServer(qi, config).Handler())
+}
+
+func TestServer_Complete(t *testing.T) {
+	tests := []struct {
+		givenQuery     string
+		wantStatus     int
+		wantNames      []string
+		wantNumMatches int
+	}{
+		{"q=fre", http.StatusOK, []string{"frei"}, 1},
+		{"q=frei&k=5", http.StatusOK, []string{"frei", "brei"}, 2},
+		{"q=frei&k=1", http.StatusOK, []string{"frei"}, 2},
+		{"q=Fr%C3%A9i", http.StatusOK, []string{"frei"}, 1},
+		{"q=", http.StatusOK, []string{}, 0},
+		{"q=!!", http.StatusOK, []string{}, 0},
+		{"q=frei&k=0", http.StatusBadRequest, nil, 0},
+		{"q=frei&k=many", http.StatusBadRequest, nil, 0},
+	}
+
+	ts := newTestServer(t)
+	defer ts.Close()
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i+1), func(t *testing.T) {
+			resp, err := http.Get(ts.URL + "/complete?" + tt.givenQuery)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+			if tt.wantStatus != http.StatusOK {
+				var errResp ErrorResponse
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+				assert.NotEmpty(t, errResp.Error)
+				return
+			}
+
+			var completeResp CompleteResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&completeResp))
+			names := []string{}
+			for _, match := range completeResp.Matches {
+				names = append(names, match.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantNumMatches, completeResp.NumMatches)
+		})
+	}
+}
+
+// Yorkshire matches both by its name and by its alias York, but is one match.
+func TestServer_CompleteAliases(t *testing.T) {
+	ts := newTestServerFromFile(t, "../../lecture-05/index/example_aliases.tsv")
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/complete?q=york")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	var completeResp CompleteResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&completeResp))
+	if assert.Len(t, completeResp.Matches, 1) {
+		assert.Equal(t, "Yorkshire", completeResp.Matches[0].Name)
+		assert.Equal(t, "", completeResp.Matches[0].Alias)
+	}
+	assert.Equal(t, 1, completeResp.NumMatches)
+}
+
+func TestServer_CORS(t *testing.T) {
+	ts := newTestServer(t)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodOptions, ts.URL+"/complete?q=frei", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, OPTIONS", resp.Header.Get("Access-Control-Allow-Methods"))
+
+	resp, err = http.Get(ts.URL + "/complete?q=frei")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestServer_MethodNotAllowed(t *testing.T) {
+	ts := newTestServer(t)
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/complete?q=frei", "text/plain", nil)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestServer_Static(t *testing.T) {
+	ts := newTestServer(t)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+}
+
+func TestServer_Timeout(t *testing.T) {
+	config := DefaultConfig()
+	config.HandlerTimeout = 10 * time.Millisecond
+	s := NewServer(index.NewQGramIndex(3), config)
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	rec := httptest.NewRecorder()
+	s.timeout(slow).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/complete?q=frei", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"error":"request timed out"}`, rec.Body.String())
+}